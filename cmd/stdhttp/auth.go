@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/mainden/stdhttp/pkg/httpx"
+)
+
+func authHttpClient(client httpx.HttpClient, rawURL string, headers []string, bearerTokenFile string) (string, httpx.HttpClient, error) {
+	targetURL, user, err := httpx.StripUserinfo(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	var wrappers []httpx.Wrapper
+	for _, header := range headers {
+		key, value, err := httpx.ParseHeader(header)
+		if err != nil {
+			return "", nil, err
+		}
+		wrappers = append(wrappers, httpx.WithSecretHeader(key, value))
+	}
+	if user != nil {
+		wrappers = append(wrappers, httpx.WithUserinfo(user))
+	}
+	if bearerTokenFile != "" {
+		wrappers = append(wrappers, httpx.WithBearerTokenFile(bearerTokenFile))
+	}
+	return targetURL, httpx.WrapHttpClient(client, wrappers...), nil
+}
@@ -30,7 +30,7 @@ func broker(ctx context.Context, config *configs.StdhttpBrokerConfig) {
 	go runx.AwaitDone(ctx, func() { listener.Close() })
 
 	processBrocker := controllers.NewProcessesBrokerController(config.WaitTimeout)
-	http.Handle("/", httpx.HandleEvent(httpx.HandleLogger(handlers.NewProcessesBrokerHttpHandler(processBrocker, stdout))))
+	http.Handle("/", httpx.HandleEvent(httpx.HandleLogger(httpx.HandleDecompression(handlers.NewProcessesBrokerHttpHandler(processBrocker, stdout), config.MaxBodySize))))
 	err = http.Serve(listener, http.DefaultServeMux)
 	if err != nil && !errors.Is(err, net.ErrClosed) {
 		logx.FatalContext(ctx, "Failed to serve", "error", err)
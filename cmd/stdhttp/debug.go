@@ -33,7 +33,7 @@ func debug(ctx context.Context, config *configs.StdhttpDebugConfig) {
 	}
 	go runx.AwaitDone(ctx, func() { listener.Close() })
 
-	http.Handle("/", httpx.HandleEvent(httpx.HandleLogger(handlers.NewPostTextDebugHttpHandler(stdout, stderr))))
+	http.Handle("/", httpx.HandleEvent(httpx.HandleLogger(httpx.HandleDecompression(handlers.NewPostTextDebugHttpHandler(stdout, stderr), config.MaxBodySize))))
 	err = http.Serve(listener, http.DefaultServeMux)
 	if err != nil && !errors.Is(err, net.ErrClosed) {
 		logx.FatalContext(ctx, "Failed to serve", "error", err)
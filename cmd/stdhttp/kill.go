@@ -9,6 +9,7 @@ import (
 	"github.com/mainden/stdhttp/internal/clients"
 	"github.com/mainden/stdhttp/internal/configs"
 	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
 	"github.com/mainden/stdhttp/pkg/iox"
 	"github.com/mainden/stdhttp/pkg/logx"
 )
@@ -18,12 +19,16 @@ func kill(ctx context.Context, config *configs.StdhttpKillConfig) {
 	if err != nil {
 		logx.FatalContext(ctx, "Error creating stdout output", "error", err)
 	}
+	brokerURL, httpClient, err := authHttpClient(httpx.Default, config.BrokerURL, config.BrokerHeaders, config.BrokerBearerTokenFile)
+	if err != nil {
+		logx.FatalContext(ctx, "Error configuring broker client", "error", err)
+	}
 	pid, err := strconv.ParseInt(config.Pattern, 0, 0)
 	parsed := err == nil
 	if parsed {
-		err = clients.NewProcessesBrokerHttpClient(config.BrokerURL, 0).Kill(ctx, int(pid))
+		err = clients.NewProcessesBrokerHttpClient(brokerURL, 0, httpClient).Kill(ctx, int(pid))
 	} else {
-		err = clients.NewProcessesBrokerHttpClient(config.BrokerURL, 0).KillMany(ctx, config.Pattern)
+		err = clients.NewProcessesBrokerHttpClient(brokerURL, 0, httpClient).KillMany(ctx, config.Pattern)
 	}
 	if err != nil && !errors.Is(err, models.ErrProcessNotFound) {
 		logx.FatalContext(ctx, "Error killing processes", "error", err)
@@ -9,6 +9,7 @@ import (
 	"github.com/mainden/stdhttp/internal/clients"
 	"github.com/mainden/stdhttp/internal/configs"
 	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
 	"github.com/mainden/stdhttp/pkg/iox"
 	"github.com/mainden/stdhttp/pkg/logx"
 )
@@ -18,7 +19,11 @@ func list(ctx context.Context, config *configs.StdhttpListConfig) {
 	if err != nil {
 		logx.FatalContext(ctx, "Error creating stdout output", "error", err)
 	}
-	processes, err := clients.NewProcessesBrokerHttpClient(config.BrokerURL, 0).List(ctx)
+	brokerURL, httpClient, err := authHttpClient(httpx.Default, config.BrokerURL, config.BrokerHeaders, config.BrokerBearerTokenFile)
+	if err != nil {
+		logx.FatalContext(ctx, "Error configuring broker client", "error", err)
+	}
+	processes, err := clients.NewProcessesBrokerHttpClient(brokerURL, 0, httpClient).List(ctx)
 	if err != nil {
 		logx.FatalContext(ctx, "Error listing processes", "error", err)
 	}
@@ -31,7 +36,7 @@ func list(ctx context.Context, config *configs.StdhttpListConfig) {
 
 func listProcessesFormat(processes models.ProcessModels) string {
 	var builder strings.Builder
-	fmt.Fprintf(&builder, "%-12v %-12v %v\n", "PID", "CLIENT NAME", "COMMAND")
+	fmt.Fprintf(&builder, "%-12v %-12v %-16v %-8v %-8v %v\n", "PID", "CLIENT NAME", "STATUS", "CPU", "RSS", "COMMAND")
 	for _, process := range processes {
 		fmt.Fprint(&builder, listProcessFormat(process))
 	}
@@ -40,7 +45,41 @@ func listProcessesFormat(processes models.ProcessModels) string {
 }
 
 func listProcessFormat(process models.ProcessModel) string {
-	return fmt.Sprintf("%-12v %-12v %v\n", process.Pid, process.ClientName, listCommandFormat(process))
+	cpu, rss := "-", "-"
+	if sample := process.LastSample; sample != nil {
+		cpu = fmt.Sprintf("%.1f%%", sample.CPUUsage*100)
+		rss = listSizeFormat(sample.RSS)
+	}
+	return fmt.Sprintf("%-12v %-12v %-16v %-8v %-8v %v\n", process.Pid, process.ClientName, listStatusFormat(process), cpu, rss, listCommandFormat(process))
+}
+
+func listSizeFormat(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", value, "KMGT"[exp])
+}
+
+func listStatusFormat(process models.ProcessModel) string {
+	event := process.LastEvent
+	switch {
+	case event == nil:
+		return "-"
+	case event.Type == models.ProcessEventExited && event.Signal != "":
+		return fmt.Sprintf("%v (%v)", event.Type, event.Signal)
+	case event.Type == models.ProcessEventExited:
+		return fmt.Sprintf("%v (%v)", event.Type, event.ExitCode)
+	case event.Type == models.ProcessEventRestarting:
+		return fmt.Sprintf("%v (#%v)", event.Type, event.Attempt)
+	default:
+		return string(event.Type)
+	}
 }
 
 func listCommandFormat(process models.ProcessModel) string {
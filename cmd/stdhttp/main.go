@@ -2,26 +2,35 @@ package main
 
 import (
 	"context"
+	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/mainden/stdhttp/pkg/flagx"
 	"github.com/mainden/stdhttp/pkg/logx"
+	"github.com/mainden/stdhttp/pkg/osx/execx"
 	"github.com/mainden/stdhttp/pkg/pubsubx"
 	"github.com/mainden/stdhttp/pkg/runx"
 )
 
 func main() {
-	defer logx.Close()
+	execx.ExecRlimits()
 	ctx := logx.WithName(pubsubx.WithCancel(context.Background()), "main")
 	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	go runx.AwaitDone(ctx, stop)
 	config := configure()
+	if err := configureHttpClient(&config.Client); err != nil {
+		logx.FatalContext(ctx, "Error configuring HTTP client", "error", err)
+	}
+	if config.Client.TLSInsecureSkipVerify {
+		logx.WarnContext(ctx, "TLS certificate verification is disabled")
+	}
+	exitCode := 0
 	switch flagx.GetStoredCommand() {
 	case "debug":
 		debug(ctx, &config.Debug)
 	case "run":
-		run(ctx, &config.Run)
+		exitCode = run(ctx, &config.Run)
 	case "broker":
 		broker(ctx, &config.Broker)
 	case "list":
@@ -31,4 +40,6 @@ func main() {
 	default:
 		panic("unknown command")
 	}
+	logx.Close()
+	os.Exit(exitCode)
 }
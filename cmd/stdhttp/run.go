@@ -2,19 +2,35 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/mainden/stdhttp/internal/clients"
 	"github.com/mainden/stdhttp/internal/configs"
 	"github.com/mainden/stdhttp/internal/handlers"
 	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
 	"github.com/mainden/stdhttp/pkg/iox"
 	"github.com/mainden/stdhttp/pkg/logx"
+	"github.com/mainden/stdhttp/pkg/osx/envx"
 	"github.com/mainden/stdhttp/pkg/osx/execx"
 	"github.com/mainden/stdhttp/pkg/pubsubx"
 	"github.com/mainden/stdhttp/pkg/runx"
+	"github.com/mainden/stdhttp/pkg/slicesx"
+	"github.com/mainden/stdhttp/pkg/spoolx"
 	"github.com/mainden/stdhttp/pkg/textx"
 )
 
@@ -22,37 +38,230 @@ var (
 	TopicStdoutLine    = "stdout.line"
 	TopicStderrLine    = "stderr.line"
 	TopicBrokerCommand = "broker.command"
+	TopicProcessEvent  = "process.event"
+	TopicProcessSample = "process.sample"
 )
 
-func run(ctx context.Context, config *configs.StdhttpRunConfig) {
-	if config.StdoutURL != "" {
-		pubsubx.Subscribe(ctx, TopicStdoutLine, handlers.NewPostTextPubsubHandler(config.StdoutURL, "stdout"))
+const (
+	runPtyDrainTimeout = time.Second
+	// The kernel rejects CPU quotas below 1ms per 100ms period
+	runCPUMaxMin = 0.01
+)
+
+func run(ctx context.Context, config *configs.StdhttpRunConfig) int {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logx.WarnContext(ctx, "Failed to get hostname", "error", err)
+	}
+	process := &models.PostTextBodyProcess{
+		Pid:         os.Getpid(),
+		ClientName:  config.BrokerClientName,
+		CommandName: config.CommandName,
+		CommandArgs: config.CommandArgs,
+		Hostname:    hostname,
+	}
+	for _, url := range slicesx.Distinct(config.StdoutURLs) {
+		unsubscribe := runSubscribeText(ctx, config, TopicStdoutLine, url, "stdout", config.StdoutFormat, config.StdoutHeaders, config.StdoutBearerTokenFile, process)
+		defer unsubscribe()
 	}
-	if config.StderrURL != "" {
-		pubsubx.Subscribe(ctx, TopicStderrLine, handlers.NewPostTextPubsubHandler(config.StderrURL, "stderr"))
+	for _, url := range slicesx.Distinct(config.StderrURLs) {
+		unsubscribe := runSubscribeText(ctx, config, TopicStderrLine, url, "stderr", config.StderrFormat, config.StderrHeaders, config.StderrBearerTokenFile, process)
+		defer unsubscribe()
+	}
+	for _, url := range slicesx.Distinct(config.EventsURLs) {
+		unsubscribe := runSubscribeProcess(ctx, config, TopicProcessEvent, url, config.EventsHeaders, config.EventsBearerTokenFile, func(url string, client httpx.HttpClient) pubsubx.Handler {
+			return handlers.NewPostProcessEventPubsubHandler(url, process, client)
+		})
+		defer unsubscribe()
+	}
+	for _, url := range slicesx.Distinct(config.MetricsURLs) {
+		unsubscribe := runSubscribeProcess(ctx, config, TopicProcessSample, url, config.MetricsHeaders, config.MetricsBearerTokenFile, func(url string, client httpx.HttpClient) pubsubx.Handler {
+			return handlers.NewPostProcessSamplePubsubHandler(url, process, client)
+		})
+		defer unsubscribe()
 	}
 	if config.BrokerURL != "" {
-		processesClient := clients.NewProcessesBrokerHttpClient(config.BrokerURL, config.BrokerWaitTimeout)
+		brokerURL, httpClient, err := authHttpClient(runHttpClient(config), config.BrokerURL, config.BrokerHeaders, config.BrokerBearerTokenFile)
+		if err != nil {
+			logx.FatalContext(ctx, "Error configuring broker client", "error", err)
+		}
+		processesClient := clients.NewProcessesBrokerHttpClient(brokerURL, config.BrokerWaitTimeout, httpClient)
 		process := models.ProcessModel{
 			Pid:         os.Getpid(),
 			ClientName:  config.BrokerClientName,
 			CommandName: config.CommandName,
 			CommandArgs: config.CommandArgs,
-			Persistent:  config.Persistent,
+			Restart: models.ProcessRestartModel{
+				Policy:      config.RestartPolicy,
+				MinDelay:    config.RestartMinDelay,
+				MaxDelay:    config.RestartMaxDelay,
+				MaxRestarts: config.RestartMaxCount,
+				Window:      config.RestartWindow,
+				ResetAfter:  config.RestartResetAfter,
+			},
 		}
+		send := func(ctx context.Context, message interface{}) error {
+			switch message := message.(type) {
+			case models.ProcessEventModel:
+				return processesClient.Event(ctx, process.Pid, message)
+			case models.ProcessSampleModel:
+				return processesClient.Sample(ctx, process.Pid, message)
+			}
+			return fmt.Errorf("%w (%T)", pubsubx.ErrUnexpectedMessageType, message)
+		}
+		messages := pubsubx.NewQueue(pubsubx.HandlerFunc(func(eventCtx context.Context, message interface{}) error {
+			err := send(eventCtx, message)
+			if errors.Is(err, models.ErrProcessNotFound) && ctx.Err() == nil {
+				if err := processesClient.Register(eventCtx, process); err != nil && !errors.Is(err, models.ErrProcessExists) {
+					return err
+				}
+				err = send(eventCtx, message)
+			}
+			return err
+		}), config.QueueSize, pubsubx.QueuePolicyDropOldest, 0)
+		pubsubx.Subscribe(ctx, TopicProcessEvent, messages)
+		pubsubx.Subscribe(ctx, TopicProcessSample, messages)
 		defer runx.Await(runx.Async(func() { processesClient.CommandLoop(ctx, process, TopicBrokerCommand) }))
 		defer pubsubx.Cancel(ctx)
+		defer iox.Close(messages)
 	}
 
+	var exitCode int
 	switch {
 	case config.CommandName != "":
-		runCommand(ctx, config)
+		exitCode = runCommand(ctx, config)
 	default:
-		runPipe(ctx, config)
+		exitCode = runPipe(ctx, config)
+	}
+	if config.IgnoreExitCode {
+		return 0
+	}
+	return exitCode
+}
+
+func runHttpClient(config *configs.StdhttpRunConfig) httpx.HttpClient {
+	return httpx.WrapHttpClient(httpx.Default, httpx.WithRetry(config.RetryAttempts, config.RetryMaxDelay), httpx.WithCircuitBreaker(config.CircuitBreakerThreshold, config.CircuitBreakerCooldown), httpx.WithCompression(config.Compression, config.CompressionMinSize))
+}
+
+func runSpoolName(source string, url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return source + "-" + hex.EncodeToString(hash[:8])
+}
+
+func runSubscribeText(ctx context.Context, config *configs.StdhttpRunConfig, topic string, rawURL string, source string, format string, headers []string, bearerTokenFile string, process *models.PostTextBodyProcess) func() {
+	url, client, err := authHttpClient(runHttpClient(config), rawURL, headers, bearerTokenFile)
+	if err != nil {
+		logx.FatalContext(ctx, "Error configuring client", "source", source, "error", err)
+	}
+	sinkType, sinkURL, err := handlers.ParsePostTextSink(url)
+	if err != nil {
+		logx.FatalContext(ctx, "Error parsing sink", "url", url, "error", err)
+	}
+	encoder, err := handlers.NewPostTextSinkEncoder(sinkType, handlers.PostTextSinkOptions{
+		Format:             format,
+		ElasticsearchIndex: config.ElasticsearchIndex,
+		SplunkSourcetype:   config.SplunkSourcetype,
+		SplunkHost:         config.SplunkHost,
+	})
+	if err != nil {
+		logx.FatalContext(ctx, "Error creating encoder", "sink", sinkType, "format", format, "error", err)
+	}
+	if sinkType == handlers.PostTextSinkSplunk {
+		switch {
+		case config.SplunkTokenFile != "":
+			client = httpx.WrapHttpClient(client, httpx.WithAuthorizationFile("Splunk", config.SplunkTokenFile))
+		case config.SplunkToken != "":
+			client = httpx.WrapHttpClient(client, httpx.WithAuthorization("Splunk", config.SplunkToken))
+		default:
+			logx.FatalContext(ctx, "Splunk token is required", "url", url)
+		}
+	}
+	poster := handlers.NewPostTextPubsubHandler(sinkURL, source, process, encoder, client)
+	var sink pubsubx.Handler = poster
+	if config.SpoolDir != "" {
+		spool, err := spoolx.Open(filepath.Join(config.SpoolDir, runSpoolName(source, url)), config.SpoolMaxSize)
+		if err != nil {
+			logx.FatalContext(ctx, "Error opening spool", "dir", config.SpoolDir, "error", err)
+		}
+		if n := spool.Len(); n > 0 {
+			logx.InfoContext(ctx, "Resuming delivery of spooled lines", "topic", topic, "url", url, "batches", n)
+		}
+		sink = handlers.NewSpoolPostTextPubsubHandler(poster, spool, source, process)
+	}
+	batcher := textx.NewTextBatcher(sink, config.BatchMaxItems, config.BatchMaxBytes, config.BatchFlushInterval)
+	queue := pubsubx.NewQueue(batcher, config.QueueSize, pubsubx.QueuePolicy(config.QueuePolicy), config.QueueSampleRate)
+	pubsubx.Subscribe(ctx, topic, queue)
+	return func() {
+		iox.Close(queue, batcher, sink)
+		if dropped := queue.Dropped(); dropped > 0 {
+			logx.WarnContext(ctx, "Dropped lines", "topic", topic, "url", url, "dropped", dropped)
+		} else {
+			logx.DebugContext(ctx, "No lines dropped", "topic", topic, "url", url)
+		}
+	}
+}
+
+func runSubscribeProcess(ctx context.Context, config *configs.StdhttpRunConfig, topic string, rawURL string, headers []string, bearerTokenFile string, newHandler func(url string, client httpx.HttpClient) pubsubx.Handler) func() {
+	url, client, err := authHttpClient(runHttpClient(config), rawURL, headers, bearerTokenFile)
+	if err != nil {
+		logx.FatalContext(ctx, "Error configuring client", "topic", topic, "error", err)
+	}
+	queue := pubsubx.NewQueue(newHandler(url, client), config.QueueSize, pubsubx.QueuePolicyDropOldest, 0)
+	pubsubx.Subscribe(ctx, topic, queue)
+	return func() {
+		iox.Close(queue)
+		if dropped := queue.Dropped(); dropped > 0 {
+			logx.WarnContext(ctx, "Dropped messages", "topic", topic, "url", url, "dropped", dropped)
+		}
+	}
+}
+
+func runPublishEvent(ctx context.Context, event models.ProcessEventModel) {
+	event.Time = time.Now()
+	if err := pubsubx.Publish(context.WithoutCancel(ctx), TopicProcessEvent, event); err != nil {
+		logx.DebugContext(ctx, "Failed to publish event", "type", event.Type, "error", err)
+	}
+}
+
+func runSampleProcess(ctx context.Context, cmd *exec.Cmd, attempt int, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var previous execx.Sample
+	previousTime := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			sample, err := execx.SampleTree(cmd.Process.Pid)
+			if errors.Is(err, execx.ErrSampleNotSupported) {
+				logx.WarnContext(ctx, "Process sampling is not supported")
+				return
+			}
+			if err != nil {
+				logx.DebugContext(ctx, "Failed to sample process", "pid", cmd.Process.Pid, "error", err)
+				continue
+			}
+			usage := float64(sample.CPUTime-previous.CPUTime) / float64(now.Sub(previousTime))
+			previous, previousTime = sample, now
+			model := models.ProcessSampleModel{
+				Time:      now,
+				ChildPid:  cmd.Process.Pid,
+				Attempt:   attempt,
+				Processes: sample.Processes,
+				CPUTime:   sample.CPUTime,
+				CPUUsage:  max(usage, 0),
+				RSS:       sample.RSS,
+				OpenFDs:   sample.OpenFDs,
+			}
+			if err := pubsubx.Publish(context.WithoutCancel(ctx), TopicProcessSample, model); err != nil {
+				logx.DebugContext(ctx, "Failed to publish sample", "error", err)
+			}
+		}
 	}
 }
 
-func runCommand(ctx context.Context, config *configs.StdhttpRunConfig) {
+func runCommand(ctx context.Context, config *configs.StdhttpRunConfig) (exitCode int) {
 	ctx = logx.WithName(ctx, "run")
 	stdout, err := iox.Output(config.StdoutOutput)
 	if err != nil {
@@ -65,80 +274,450 @@ func runCommand(ctx context.Context, config *configs.StdhttpRunConfig) {
 	}
 	defer iox.Close(stderr)
 
-	if config.StdoutURL != "" {
+	if len(config.StdoutURLs) > 0 {
 		r, w := io.Pipe()
 		go runx.AwaitDone(ctx, func() { iox.Close(w) })
-		stdout = io.MultiWriter(stdout, w)
-		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStdoutLine).Run(context.Background()) }))
+		stdout = io.MultiWriter(stdout, iox.SelectWriter(config.StripAnsi, textx.NewAnsiWriter(w), w))
+		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStdoutLine, runScannerOptions(config)).Run(context.Background()) }))
+		defer iox.Close(w)
 	}
-	if config.StderrURL != "" {
+	if len(config.StderrURLs) > 0 {
 		r, w := io.Pipe()
 		go runx.AwaitDone(ctx, func() { iox.Close(w) })
-		stderr = io.MultiWriter(stderr, w)
-		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStderrLine).Run(context.Background()) }))
+		stderr = io.MultiWriter(stderr, iox.SelectWriter(config.StripAnsi, textx.NewAnsiWriter(w), w))
+		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStderrLine, runScannerOptions(config)).Run(context.Background()) }))
+		defer iox.Close(w)
+	}
+
+	var pty *execx.Pty
+	if config.Tty {
+		if pty, err = execx.OpenPty(); err != nil {
+			logx.FatalContext(ctx, "Error opening pty", "error", err)
+		}
+		copied := runx.Async(func() {
+			if _, err := io.Copy(stdout, pty); err != nil {
+				logx.DebugContext(ctx, "Failed to copy pty output", "error", err)
+			}
+		})
+		defer iox.Close(pty)
+		defer runx.AwaitWithTimeout(runPtyDrainTimeout, copied)
+		defer pty.CloseTty()
+		go runCopyPtyInput(ctx, pty)
+		go runResizePty(ctx, pty)
+	}
+
+	// Users are resolved before the terminal is set to raw mode, which a fatal error would not restore
+	credential, err := execx.LookupCredential(config.User, config.Group)
+	if err != nil {
+		logx.FatalContext(ctx, "Error configuring user", "user", config.User, "group", config.Group, "error", err)
+	}
+	env, err := runEnviron(config, credential)
+	if err != nil {
+		logx.FatalContext(ctx, "Error preparing environment", "error", err)
+	}
+	stopSignal, err := execx.ParseSignal(config.StopSignal)
+	if err != nil {
+		logx.FatalContext(ctx, "Error parsing stop signal", "error", err)
+	}
+	var parentDeathSignal syscall.Signal
+	if config.ParentDeathSignal != "" {
+		if parentDeathSignal, err = execx.ParseSignal(config.ParentDeathSignal); err != nil {
+			logx.FatalContext(ctx, "Error parsing parent death signal", "error", err)
+		}
+	}
+	var reaper *execx.Reaper
+	if config.Init {
+		if reaper, err = execx.NewReaper(); err != nil {
+			logx.FatalContext(ctx, "Error starting reaper", "error", err)
+		}
+		defer func() {
+			iox.Close(reaper)
+			logx.DebugContext(ctx, "Reaper stopped", "reaped", reaper.Reaped())
+		}()
+	}
+	rlimits, err := execx.ParseRlimits(config.Rlimits)
+	if err != nil {
+		logx.FatalContext(ctx, "Error parsing resource limits", "error", err)
+	}
+	cpuMax, err := runParseCPUMax(config.CPUMax)
+	if err != nil {
+		logx.FatalContext(ctx, "Error parsing CPU limit", "error", err)
+	}
+	var cgroups *execx.Cgroups
+	if config.MemoryMax > 0 || cpuMax > 0 || config.PidsMax > 0 {
+		cgroups, err = execx.NewCgroups(execx.CgroupOptions{
+			Parent:    config.CgroupParent,
+			MemoryMax: config.MemoryMax,
+			CPUMax:    cpuMax,
+			PidsMax:   config.PidsMax,
+		})
+		if err != nil {
+			logx.WarnContext(ctx, "Cgroup limits are disabled", "error", err)
+		}
+		defer func() {
+			if err := cgroups.Close(); err != nil {
+				logx.WarnContext(ctx, "Failed to restore cgroup", "error", err)
+			}
+		}()
+	}
+	forward := make(chan os.Signal, 1)
+	// Notify without signals would subscribe to all of them
+	if len(execx.ForwardSignals) > 0 {
+		signal.Notify(forward, execx.ForwardSignals...)
+		defer signal.Stop(forward)
 	}
 
 	logx.InfoContext(ctx, "Starting command", "name", config.CommandName, "args", config.CommandArgs)
 	group := execx.NewProcessGroup()
 	defer group.Close()
-	for done := false; config.Persistent || !done; done = true {
-		if done {
-			logx.InfoContext(ctx, "Restarting command", "name", config.CommandName, "args", config.CommandArgs)
+	defer func() { logx.InfoContext(ctx, "Command exit code", "name", config.CommandName, "exit_code", exitCode) }()
+	restarter := runx.NewRestarter(runRestartOptions(config))
+	var uptime time.Duration
+	var cgroup *execx.Cgroup
+	defer func() { runCloseCgroup(ctx, cgroup) }()
+	if pty != nil && execx.IsTerminal(os.Stdin.Fd()) {
+		// Keys go to the pty as they are typed, which echoes and interprets them for the command
+		if restore, err := execx.MakeRaw(os.Stdin.Fd()); err != nil {
+			logx.WarnContext(ctx, "Failed to set terminal raw mode", "error", err)
+		} else {
+			defer restore()
 		}
+	}
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if !restarter.ShouldRestart(exitCode != 0) {
+				return exitCode
+			}
+			if restarter.LimitReached(time.Now()) {
+				logx.WarnContext(ctx, "Restart limit reached", "name", config.CommandName, "args", config.CommandArgs, "max_count", config.RestartMaxCount, "window", config.RestartWindow)
+				return exitCode
+			}
+			delay := restarter.Next(time.Now(), uptime)
+			logx.InfoContext(ctx, "Restarting command", "name", config.CommandName, "args", config.CommandArgs, "attempt", attempt, "delay", delay)
+			runPublishEvent(ctx, models.ProcessEventModel{Type: models.ProcessEventRestarting, Attempt: attempt, Delay: delay})
+			runx.AwaitDoneWithTimeout(ctx, delay)
+			if ctx.Err() != nil {
+				logx.InfoContext(ctx, "Command cancelled", "name", config.CommandName, "args", config.CommandArgs)
+				return exitCode
+			}
+		}
+
+		runCloseCgroup(ctx, cgroup)
+		cgroup = runNewCgroup(ctx, cgroups, attempt)
 
 		cmd := exec.CommandContext(ctx, config.CommandName, config.CommandArgs...)
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = stdout
 		cmd.Stderr = stderr
+		cmd.Dir = config.Workdir
+		cmd.Env = env
+		execx.CmdCredential(cmd, credential)
 		execx.CmdHide(cmd)
+		if pty != nil {
+			pty.Prepare(cmd)
+		}
+		execx.CmdStop(cmd, stopSignal, config.StopTimeout)
+		if parentDeathSignal != 0 {
+			execx.CmdParentDeathSignal(cmd, parentDeathSignal)
+		}
+		execStatus, err := execx.CmdRlimits(cmd, rlimits)
+		if err != nil {
+			logx.ErrorContext(ctx, "Failed to start command", "name", config.CommandName, "args", config.CommandArgs, "error", err)
+			runPublishEvent(ctx, models.ProcessEventModel{Type: models.ProcessEventStartFailed, Attempt: attempt, Error: err.Error()})
+			exitCode, uptime = execx.ExitCodeStartFailed, 0
+			continue
+		}
+		if cgroup != nil {
+			cgroup.Prepare(cmd)
+		}
+		group.Prepare(cmd)
 
-		if err := cmd.Start(); err != nil && ctx.Err() == nil {
+		if err := runStart(cmd, reaper, execStatus); err != nil && ctx.Err() == nil {
 			logx.ErrorContext(ctx, "Failed to start command", "name", config.CommandName, "args", config.CommandArgs, "error", err)
+			runReleaseProcessGroup(ctx, config, group, cmd)
+			runPublishEvent(ctx, models.ProcessEventModel{Type: models.ProcessEventStartFailed, Attempt: attempt, Error: err.Error()})
+			exitCode, uptime = execx.ExitCodeStartFailed, 0
 			continue
 		} else if ctx.Err() == nil {
 			logx.InfoContext(ctx, "Command started", "name", config.CommandName, "args", config.CommandArgs)
+			runPublishEvent(ctx, models.ProcessEventModel{Type: models.ProcessEventStarted, Attempt: attempt, ChildPid: cmd.Process.Pid})
 		} else {
 			logx.InfoContext(ctx, "Command cancelled", "name", config.CommandName, "args", config.CommandArgs)
-			return
+			return exitCode
 		}
+		started := time.Now()
 
 		if err := group.Add(cmd); err != nil {
 			logx.ErrorContext(ctx, "Failed to add command to process group", "name", config.CommandName, "args", config.CommandArgs, "error", err)
 		}
 
-		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
-			logx.ErrorContext(ctx, "Command failed", "name", config.CommandName, "args", config.CommandArgs, "error", err)
+		done := make(chan struct{})
+		go runForwardSignals(ctx, cmd, forward, done)
+		if config.MetricsInterval > 0 {
+			go runSampleProcess(ctx, cmd, attempt, config.MetricsInterval, done)
+		}
+		err = runWait(cmd, reaper)
+		close(done)
+		runReleaseProcessGroup(ctx, config, group, cmd)
+		uptime = time.Since(started)
+		stats := runCgroupStats(ctx, cgroup)
+		runPublishEvent(ctx, runExitedEvent(cmd, attempt, uptime, stats, err))
+		usage := execx.ProcessUsage(cmd.ProcessState)
+		args := []any{"name", config.CommandName, "args", config.CommandArgs, "user_time", usage.UserTime, "system_time", usage.SystemTime, "max_rss", usage.MaxRSS}
+		if cgroup != nil {
+			args = append(args, "memory_peak", stats.MemoryPeak, "oom_killed", stats.OOMKills > 0)
+		}
+		if err != nil && ctx.Err() == nil {
+			logx.ErrorContext(ctx, "Command failed", append(args, "error", err)...)
+			exitCode = execx.ExitCode(cmd.ProcessState)
 			continue
 		} else if ctx.Err() == nil {
-			logx.InfoContext(ctx, "Command succeeded", "name", config.CommandName, "args", config.CommandArgs)
+			logx.InfoContext(ctx, "Command succeeded", args...)
+			exitCode = 0
 			continue
 		} else {
 			logx.InfoContext(ctx, "Command cancelled", "name", config.CommandName, "args", config.CommandArgs)
+			exitCode = execx.ExitCode(cmd.ProcessState)
+			return exitCode
+		}
+	}
+}
+
+func runScannerOptions(config *configs.StdhttpRunConfig) textx.TextScannerOptions {
+	return textx.TextScannerOptions{
+		MaxLineSize: config.MaxLineSize,
+		LinePolicy:  textx.LinePolicy(config.LinePolicy),
+		Binary:      config.Binary,
+	}
+}
+
+func runEnviron(config *configs.StdhttpRunConfig, credential *execx.Credential) ([]string, error) {
+	env := os.Environ()
+	if config.ClearEnv {
+		env = envx.Allow(env, config.EnvAllow)
+	}
+	if credential != nil && credential.User != nil {
+		u := credential.User
+		if u.HomeDir != "" {
+			env = append(env, "HOME="+u.HomeDir)
+		}
+		env = append(env, "USER="+u.Username, "LOGNAME="+u.Username)
+	}
+	env = append(env, "STDHTTP_PID="+strconv.Itoa(os.Getpid()))
+	if config.BrokerURL != "" {
+		env = append(env, "STDHTTP_BROKER_URL="+config.BrokerURL)
+	}
+	if config.BrokerClientName != "" {
+		env = append(env, "STDHTTP_CLIENT_NAME="+config.BrokerClientName)
+	}
+	for _, path := range config.EnvFiles {
+		vars, err := envx.ReadDotenv(path, func(key string) (string, bool) { return envx.Lookup(env, key) })
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, vars...)
+	}
+	for _, entry := range config.Env {
+		if !strings.Contains(entry, "=") {
+			return nil, fmt.Errorf("%w (%s)", envx.ErrInvalidEnv, entry)
+		}
+		env = append(env, entry)
+	}
+	return env, nil
+}
+
+func runCopyPtyInput(ctx context.Context, pty *execx.Pty) {
+	if _, err := io.Copy(pty, iox.NewContextReader(ctx, os.Stdin)); err != nil {
+		return
+	}
+	// Signal end of input to the command reading from the pty
+	_, _ = pty.Write([]byte{4})
+}
+
+func runResizePty(ctx context.Context, pty *execx.Pty) {
+	files := []*os.File{os.Stdin, os.Stdout, os.Stderr}
+	i := slices.IndexFunc(files, func(file *os.File) bool { return execx.IsTerminal(file.Fd()) })
+	if i < 0 {
+		return
+	}
+	fd := files[i].Fd()
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, execx.ResizeSignals...)
+	defer signal.Stop(resize)
+	for {
+		if err := pty.Resize(fd); err != nil {
+			logx.DebugContext(ctx, "Failed to resize pty", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-resize:
+		}
+	}
+}
+
+func runNewCgroup(ctx context.Context, cgroups *execx.Cgroups, attempt int) *execx.Cgroup {
+	if cgroups == nil {
+		return nil
+	}
+	cgroup, err := cgroups.New(fmt.Sprintf("stdhttp-%d-%d", os.Getpid(), attempt))
+	if err != nil {
+		logx.WarnContext(ctx, "Failed to create cgroup", "error", err)
+		return nil
+	}
+	return cgroup
+}
+
+func runParseCPUMax(value string) (float64, error) {
+	cpuMax, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if cpuMax != 0 && !(cpuMax >= runCPUMaxMin && !math.IsInf(cpuMax, 1)) {
+		return 0, fmt.Errorf("CPU limit %v must be zero or at least %v", value, runCPUMaxMin)
+	}
+	return cpuMax, nil
+}
+
+func runCgroupStats(ctx context.Context, cgroup *execx.Cgroup) execx.CgroupStats {
+	if cgroup == nil {
+		return execx.CgroupStats{}
+	}
+	stats, err := cgroup.Stats()
+	if err != nil {
+		logx.DebugContext(ctx, "Failed to read cgroup stats", "error", err)
+	}
+	return stats
+}
+
+func runCloseCgroup(ctx context.Context, cgroup *execx.Cgroup) {
+	if cgroup == nil {
+		return
+	}
+	if err := cgroup.Close(); err != nil {
+		logx.DebugContext(ctx, "Failed to close cgroup", "error", err)
+	}
+}
+
+func runStart(cmd *exec.Cmd, reaper *execx.Reaper, status *execx.ExecStatus) error {
+	var err error
+	if reaper != nil {
+		err = reaper.Start(cmd)
+	} else {
+		err = cmd.Start()
+	}
+	if status == nil {
+		return err
+	}
+	if err != nil {
+		iox.Close(status)
+		return err
+	}
+	// The helper applying resource limits reports a failed exec before the command runs
+	if err := status.Wait(); err != nil {
+		_ = runWait(cmd, reaper)
+		return err
+	}
+	return nil
+}
+
+func runReleaseProcessGroup(ctx context.Context, config *configs.StdhttpRunConfig, group *execx.ProcessGroup, cmd *exec.Cmd) {
+	if err := group.Release(cmd); err != nil {
+		logx.WarnContext(ctx, "Failed to release process group", "name", config.CommandName, "args", config.CommandArgs, "error", err)
+	}
+}
+
+func runWait(cmd *exec.Cmd, reaper *execx.Reaper) error {
+	var err error
+	if reaper != nil {
+		err = reaper.Wait(cmd)
+	} else {
+		err = cmd.Wait()
+	}
+	// The stop timeout also bounds copying output after the command exits, descendants keeping the output open do not fail it
+	if errors.Is(err, exec.ErrWaitDelay) && cmd.ProcessState != nil && cmd.ProcessState.Success() {
+		return nil
+	}
+	return err
+}
+
+func runForwardSignals(ctx context.Context, cmd *exec.Cmd, forward <-chan os.Signal, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
 			return
+		case received := <-forward:
+			if err := execx.Signal(cmd, received); err != nil {
+				logx.DebugContext(ctx, "Failed to forward signal", "signal", received, "error", err)
+				continue
+			}
+			logx.DebugContext(ctx, "Forwarded signal", "signal", received)
 		}
 	}
 }
 
-func runPipe(ctx context.Context, config *configs.StdhttpRunConfig) {
+func runRestartOptions(config *configs.StdhttpRunConfig) runx.RestartOptions {
+	return runx.RestartOptions{
+		Policy:      runx.RestartPolicy(config.RestartPolicy),
+		MinDelay:    config.RestartMinDelay,
+		MaxDelay:    config.RestartMaxDelay,
+		MaxRestarts: config.RestartMaxCount,
+		Window:      config.RestartWindow,
+		ResetAfter:  config.RestartResetAfter,
+	}
+}
+
+func runExitedEvent(cmd *exec.Cmd, attempt int, duration time.Duration, stats execx.CgroupStats, err error) models.ProcessEventModel {
+	event := models.ProcessEventModel{
+		Type:       models.ProcessEventExited,
+		Attempt:    attempt,
+		ChildPid:   cmd.Process.Pid,
+		ExitCode:   cmd.ProcessState.ExitCode(),
+		Duration:   duration,
+		MemoryPeak: stats.MemoryPeak,
+		OOMKilled:  stats.OOMKills > 0,
+	}
+	usage := execx.ProcessUsage(cmd.ProcessState)
+	event.Usage = &models.ProcessUsageModel{
+		UserTime:                   usage.UserTime,
+		SystemTime:                 usage.SystemTime,
+		MaxRSS:                     usage.MaxRSS,
+		VoluntaryContextSwitches:   usage.VoluntaryContextSwitches,
+		InvoluntaryContextSwitches: usage.InvoluntaryContextSwitches,
+	}
+	if signal, ok := execx.ExitSignal(cmd.ProcessState); ok {
+		event.Signal = execx.SignalName(signal)
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+func runPipe(ctx context.Context, config *configs.StdhttpRunConfig) int {
 	stdout, err := iox.Output(config.StdoutOutput)
 	if err != nil {
 		logx.FatalContext(ctx, "Error creating stdout output", "error", err)
 	}
 	defer iox.Close(stdout)
 
-	if config.StdoutURL != "" {
+	if len(config.StdoutURLs) > 0 {
 		r, w := io.Pipe()
 		go runx.AwaitDone(ctx, func() { iox.Close(w) })
 		stdout = io.MultiWriter(stdout, w)
-		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStdoutLine).Run(context.Background()) }))
+		defer runx.Await(runx.Async(func() { textx.NewTextScanner(r, TopicStdoutLine, runScannerOptions(config)).Run(context.Background()) }))
+		defer iox.Close(w)
 	}
 
 	logx.InfoContext(ctx, "Piping stdin")
 	if _, err := io.Copy(stdout, iox.NewContextReader(ctx, os.Stdin)); err != nil && ctx.Err() == nil {
 		logx.ErrorContext(ctx, "Pipe failed", "error", err)
+		return 1
 	} else if ctx.Err() == nil {
 		logx.InfoContext(ctx, "Pipe succeeded")
 	} else {
 		logx.InfoContext(ctx, "Pipe cancelled")
 	}
+	return 0
 }
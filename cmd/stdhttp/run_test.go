@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/configs"
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/osx/execx"
+	"github.com/mainden/stdhttp/pkg/pubsubx"
+)
+
+func TestRunCommandCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+	tests := []struct {
+		script string
+		want   int
+	}{
+		{"sleep 10", execx.ExitCodeSignalBase + 15},
+		{"trap 'exit 3' TERM; sleep 10 & wait", 3},
+	}
+	for _, test := range tests {
+		config := &configs.StdhttpRunConfig{
+			CommandName:   "sh",
+			CommandArgs:   []string{"-c", test.script},
+			StdoutOutput:  "null",
+			StderrOutput:  "null",
+			StopSignal:    "SIGTERM",
+			StopTimeout:   5 * time.Second,
+			CPUMax:        "0",
+			RestartPolicy: "never",
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(200*time.Millisecond, cancel)
+		if got := runCommand(ctx, config); got != test.want {
+			t.Errorf("script %q: expected exit code %v, got %v", test.script, test.want, got)
+		}
+		timer.Stop()
+		cancel()
+	}
+}
+
+func TestRunCommandInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+	for _, stopSignal := range []string{"SIGTERM", "SIGINT"} {
+		dir := t.TempDir()
+		ready, count := filepath.Join(dir, "ready"), filepath.Join(dir, "count")
+		config := &configs.StdhttpRunConfig{
+			CommandName:   "sh",
+			CommandArgs:   []string{"-c", `n=0; trap 'n=$((n+1))' INT TERM; touch "$1"; while [ $n -eq 0 ]; do sleep 0.05; done; sleep 0.5; echo $n > "$2"`, "sh", ready, count},
+			StdoutOutput:  "null",
+			StderrOutput:  "null",
+			StopSignal:    stopSignal,
+			StopTimeout:   5 * time.Second,
+			CPUMax:        "0",
+			RestartPolicy: "never",
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			for {
+				if _, err := os.Stat(ready); err == nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if process, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = process.Signal(os.Interrupt)
+			}
+		}()
+		if got := runCommand(ctx, config); got != 0 {
+			t.Errorf("stop signal %v: expected exit code 0, got %v", stopSignal, got)
+		}
+		stop()
+		data, err := os.ReadFile(count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(data)); got != "1" {
+			t.Errorf("stop signal %v: expected the command to receive 1 signal, got %v", stopSignal, got)
+		}
+	}
+}
+
+func TestRunCommandOutputHeldOpen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not supported on windows")
+	}
+	ctx := pubsubx.WithManager(context.Background(), pubsubx.NewManager())
+	var events []models.ProcessEventModel
+	pubsubx.Subscribe(ctx, TopicProcessEvent, pubsubx.HandlerFunc(func(ctx context.Context, message interface{}) error {
+		events = append(events, message.(models.ProcessEventModel))
+		return nil
+	}))
+	config := &configs.StdhttpRunConfig{
+		CommandName:   "sh",
+		CommandArgs:   []string{"-c", "sleep 5 & exit 0"},
+		StdoutOutput:  "null",
+		StderrOutput:  "null",
+		StopSignal:    "SIGTERM",
+		StopTimeout:   100 * time.Millisecond,
+		CPUMax:        "0",
+		RestartPolicy: "on-failure",
+	}
+	if got := runCommand(ctx, config); got != 0 {
+		t.Errorf("expected exit code 0, got %v", got)
+	}
+	var exited []models.ProcessEventModel
+	for _, event := range events {
+		if event.Type == models.ProcessEventExited {
+			exited = append(exited, event)
+		}
+	}
+	if len(exited) != 1 || exited[0].Error != "" {
+		t.Errorf("expected 1 successful exit, got %+v", exited)
+	}
+}
+
+func TestRunParseCPUMax(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		err   bool
+	}{
+		{value: "0", want: 0},
+		{value: "0.5", want: 0.5},
+		{value: "0.01", want: 0.01},
+		{value: "0.001", err: true},
+		{value: "-1", err: true},
+		{value: "NaN", err: true},
+		{value: "Inf", err: true},
+		{value: "x", err: true},
+	}
+	for _, test := range tests {
+		got, err := runParseCPUMax(test.value)
+		if (err != nil) != test.err {
+			t.Errorf("%v: unexpected error: %v", test.value, err)
+		}
+		if got != test.want {
+			t.Errorf("%v: expected %v, got %v", test.value, test.want, got)
+		}
+	}
+}
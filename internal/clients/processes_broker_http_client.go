@@ -18,23 +18,33 @@ import (
 type processesBrokerHttpClient struct {
 	waitTimeout time.Duration
 	url         string
+	httpClient  httpx.HttpClient
 }
 
-func NewProcessesBrokerHttpClient(url string, waitTimeout time.Duration) *processesBrokerHttpClient {
+func NewProcessesBrokerHttpClient(url string, waitTimeout time.Duration, httpClient httpx.HttpClient) *processesBrokerHttpClient {
 	if waitTimeout <= 0 {
 		waitTimeout = 10 * time.Second
 	}
 	return &processesBrokerHttpClient{
 		url:         url,
 		waitTimeout: waitTimeout,
+		httpClient:  httpClient,
 	}
 }
 
+func (client *processesBrokerHttpClient) context(ctx context.Context) context.Context {
+	if client.httpClient == nil {
+		return ctx
+	}
+	return httpx.WithHttpClient(ctx, client.httpClient)
+}
+
 func (client *processesBrokerHttpClient) WaitTimeout() time.Duration {
 	return client.waitTimeout
 }
 
 func (client *processesBrokerHttpClient) Register(ctx context.Context, process models.ProcessModel) (err error) {
+	ctx = client.context(ctx)
 	var resp *http.Response
 	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"Register"}}.Encode(), models.MakeProcessesBodyItem(process)); err != nil {
 		return err
@@ -52,6 +62,7 @@ func (client *processesBrokerHttpClient) Register(ctx context.Context, process m
 }
 
 func (client *processesBrokerHttpClient) Kill(ctx context.Context, pid int) (err error) {
+	ctx = client.context(ctx)
 	var resp *http.Response
 	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"Kill"}, "pid": {strconv.Itoa(pid)}}.Encode(), nil); err != nil {
 		return err
@@ -69,6 +80,7 @@ func (client *processesBrokerHttpClient) Kill(ctx context.Context, pid int) (err
 }
 
 func (client *processesBrokerHttpClient) KillMany(ctx context.Context, pattern string) (err error) {
+	ctx = client.context(ctx)
 	var resp *http.Response
 	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"KillMany"}, "pattern": {pattern}}.Encode(), nil); err != nil {
 		return err
@@ -83,6 +95,7 @@ func (client *processesBrokerHttpClient) KillMany(ctx context.Context, pattern s
 }
 
 func (client *processesBrokerHttpClient) SendCommand(ctx context.Context, pid int, command string) (err error) {
+	ctx = client.context(ctx)
 	var resp *http.Response
 	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"SendCommand"}, "pid": {strconv.Itoa(pid)}}.Encode(), command); err != nil {
 		return err
@@ -103,7 +116,7 @@ func (client *processesBrokerHttpClient) SendCommand(ctx context.Context, pid in
 }
 
 func (client *processesBrokerHttpClient) WaitCommand(ctx context.Context, pid int) (command string, err error) {
-	ctx, cancel := context.WithTimeout(ctx, client.waitTimeout+time.Second)
+	ctx, cancel := context.WithTimeout(client.context(ctx), client.waitTimeout+time.Second)
 	defer cancel()
 	var resp *http.Response
 	if resp, err = httpx.DoReader(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"WaitCommand"}, "pid": {strconv.Itoa(pid)}}.Encode(), nil); err != nil {
@@ -130,7 +143,44 @@ func (client *processesBrokerHttpClient) WaitCommand(ctx context.Context, pid in
 	return "", httpx.MakeErrorUnexpectedStatusCode(resp.StatusCode)
 }
 
+func (client *processesBrokerHttpClient) Event(ctx context.Context, pid int, event models.ProcessEventModel) (err error) {
+	ctx = client.context(ctx)
+	var resp *http.Response
+	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"Event"}, "pid": {strconv.Itoa(pid)}}.Encode(), models.MakeProcessEventBodyItem(event)); err != nil {
+		return err
+	}
+	if err = httpx.AsNothing(resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return models.ErrProcessNotFound
+	}
+	return httpx.MakeErrorUnexpectedStatusCode(resp.StatusCode)
+}
+
+func (client *processesBrokerHttpClient) Sample(ctx context.Context, pid int, sample models.ProcessSampleModel) (err error) {
+	ctx = client.context(ctx)
+	var resp *http.Response
+	if resp, err = httpx.DoJson(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"Sample"}, "pid": {strconv.Itoa(pid)}}.Encode(), models.MakeProcessSampleBodyItem(sample)); err != nil {
+		return err
+	}
+	if err = httpx.AsNothing(resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return models.ErrProcessNotFound
+	}
+	return httpx.MakeErrorUnexpectedStatusCode(resp.StatusCode)
+}
+
 func (client *processesBrokerHttpClient) List(ctx context.Context) (processes models.ProcessModels, err error) {
+	ctx = client.context(ctx)
 	var resp *http.Response
 	if resp, err = httpx.DoReader(ctx, http.MethodGet, client.url+"?"+url.Values{"op": {"List"}}.Encode(), nil); err != nil {
 		return nil, err
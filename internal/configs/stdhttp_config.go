@@ -3,6 +3,7 @@ package configs
 import "time"
 
 type StdhttpConfig struct {
+	Client StdhttpClientConfig
 	Run    StdhttpRunConfig
 	Debug  StdhttpDebugConfig
 	Broker StdhttpBrokerConfig
@@ -10,40 +11,146 @@ type StdhttpConfig struct {
 	Kill   StdhttpKillConfig
 }
 
+type StdhttpClientConfig struct {
+	TLSCAFile             string
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSServerName         string
+	TLSMinVersion         string
+	TLSInsecureSkipVerify bool
+	TLSPinSHA256          []string
+
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	Proxy                 string
+	UnixSocket            string
+}
+
 type StdhttpRunConfig struct {
-	StdoutURL    string
-	StderrURL    string
+	StdoutURLs   []string
+	StderrURLs   []string
 	StdoutOutput string
 	StderrOutput string
+	StdoutFormat string
+	StderrFormat string
+	MaxLineSize  int
+	LinePolicy   string
+	Binary       bool
+
+	StdoutHeaders         []string
+	StderrHeaders         []string
+	StdoutBearerTokenFile string
+	StderrBearerTokenFile string
+
+	EventsURLs            []string
+	EventsHeaders         []string
+	EventsBearerTokenFile string
+
+	MetricsURLs            []string
+	MetricsHeaders         []string
+	MetricsBearerTokenFile string
+	MetricsInterval        time.Duration
+
+	ElasticsearchIndex string
+	SplunkToken        string
+	SplunkTokenFile    string
+	SplunkSourcetype   string
+	SplunkHost         string
+
+	BatchMaxItems      int
+	BatchMaxBytes      int
+	BatchFlushInterval time.Duration
+
+	QueueSize       int
+	QueuePolicy     string
+	QueueSampleRate int
+
+	SpoolDir     string
+	SpoolMaxSize int64
+
+	RetryAttempts int
+	RetryMaxDelay time.Duration
+
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+
+	Compression        string
+	CompressionMinSize int
 
 	CommandName string
 	CommandArgs []string
-	Persistent  bool
 
-	BrokerURL         string
-	BrokerClientName  string
-	BrokerWaitTimeout time.Duration
+	Workdir  string
+	Env      []string
+	EnvFiles []string
+	ClearEnv bool
+	EnvAllow []string
+	User     string
+	Group    string
+
+	Tty       bool
+	StripAnsi bool
+
+	RestartPolicy     string
+	RestartMinDelay   time.Duration
+	RestartMaxDelay   time.Duration
+	RestartMaxCount   int
+	RestartWindow     time.Duration
+	RestartResetAfter time.Duration
+
+	StopSignal  string
+	StopTimeout time.Duration
+
+	ParentDeathSignal string
+
+	Init bool
+
+	Rlimits      []string
+	CgroupParent string
+	MemoryMax    int64
+	CPUMax       string
+	PidsMax      int64
+
+	IgnoreExitCode bool
+
+	BrokerURL             string
+	BrokerClientName      string
+	BrokerWaitTimeout     time.Duration
+	BrokerHeaders         []string
+	BrokerBearerTokenFile string
 }
 
 type StdhttpDebugConfig struct {
 	Address      string
 	StdoutOutput string
 	StderrOutput string
+	MaxBodySize  int64
 }
 
 type StdhttpBrokerConfig struct {
 	Address      string
 	StdoutOutput string
 	WaitTimeout  time.Duration
+	MaxBodySize  int64
 }
 
 type StdhttpListConfig struct {
-	BrokerURL    string
-	StdoutOutput string
+	BrokerURL             string
+	BrokerHeaders         []string
+	BrokerBearerTokenFile string
+	StdoutOutput          string
 }
 
 type StdhttpKillConfig struct {
-	BrokerURL    string
-	StdoutOutput string
-	Pattern      string
+	BrokerURL             string
+	BrokerHeaders         []string
+	BrokerBearerTokenFile string
+	StdoutOutput          string
+	Pattern               string
 }
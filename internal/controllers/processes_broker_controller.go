@@ -154,6 +154,30 @@ func (controller *processesBrokerController) WaitCommand(ctx context.Context, pi
 	}
 }
 
+func (controller *processesBrokerController) Event(ctx context.Context, pid int, event models.ProcessEventModel) error {
+	controller.processesMutex.Lock()
+	defer controller.processesMutex.Unlock()
+	process, ok := controller.processes[pid]
+	if !ok {
+		return models.ErrProcessNotFound
+	}
+	process.LastEvent = &event
+	controller.processes[pid] = process
+	return nil
+}
+
+func (controller *processesBrokerController) Sample(ctx context.Context, pid int, sample models.ProcessSampleModel) error {
+	controller.processesMutex.Lock()
+	defer controller.processesMutex.Unlock()
+	process, ok := controller.processes[pid]
+	if !ok {
+		return models.ErrProcessNotFound
+	}
+	process.LastSample = &sample
+	controller.processes[pid] = process
+	return nil
+}
+
 func (controller *processesBrokerController) List(ctx context.Context) (models.ProcessModels, error) {
 	controller.processesMutex.RLock()
 	defer controller.processesMutex.RUnlock()
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
+	"github.com/mainden/stdhttp/pkg/logx"
+	"github.com/mainden/stdhttp/pkg/pubsubx"
+)
+
+type postProcessModelPubsubHandler[T any] struct {
+	name     string
+	url      string
+	process  *models.PostTextBodyProcess
+	client   httpx.HttpClient
+	makeBody func(process *models.PostTextBodyProcess, model T) any
+}
+
+func NewPostProcessEventPubsubHandler(url string, process *models.PostTextBodyProcess, client httpx.HttpClient) *postProcessModelPubsubHandler[models.ProcessEventModel] {
+	return &postProcessModelPubsubHandler[models.ProcessEventModel]{
+		name:    "post_process_event_pubsub_handler",
+		url:     url,
+		process: process,
+		client:  client,
+		makeBody: func(process *models.PostTextBodyProcess, event models.ProcessEventModel) any {
+			return models.MakeProcessEventBody(process, event)
+		},
+	}
+}
+
+func NewPostProcessSamplePubsubHandler(url string, process *models.PostTextBodyProcess, client httpx.HttpClient) *postProcessModelPubsubHandler[models.ProcessSampleModel] {
+	return &postProcessModelPubsubHandler[models.ProcessSampleModel]{
+		name:    "post_process_sample_pubsub_handler",
+		url:     url,
+		process: process,
+		client:  client,
+		makeBody: func(process *models.PostTextBodyProcess, sample models.ProcessSampleModel) any {
+			return models.MakeProcessSampleBody(process, sample)
+		},
+	}
+}
+
+func (h *postProcessModelPubsubHandler[T]) Handle(ctx context.Context, message interface{}) (err error) {
+	ctx = logx.WithName(ctx, h.name)
+	model, ok := message.(T)
+	if !ok {
+		return fmt.Errorf("%w (%T)", pubsubx.ErrUnexpectedMessageType, message)
+	}
+	if h.client != nil {
+		ctx = httpx.WithHttpClient(ctx, h.client)
+	}
+	ctx = httpx.WithRetryable(ctx, true)
+	var resp *http.Response
+	if resp, err = httpx.DoJson(ctx, http.MethodPost, h.url, h.makeBody(h.process, model)); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if err = httpx.AsNothing(resp.Body); err != nil {
+			return err
+		}
+		return httpx.MakeErrorUnexpectedStatusCode(resp.StatusCode)
+	}
+	return httpx.AsNothing(resp.Body)
+}
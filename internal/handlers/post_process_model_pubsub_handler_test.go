@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
+	"github.com/mainden/stdhttp/pkg/pubsubx"
+)
+
+func TestPostProcessModelPubsubHandler(t *testing.T) {
+	process := &models.PostTextBodyProcess{Pid: 42, CommandName: "echo"}
+	event := func(url string) pubsubx.Handler {
+		return NewPostProcessEventPubsubHandler(url, process, http.DefaultClient)
+	}
+	sample := func(url string) pubsubx.Handler {
+		return NewPostProcessSamplePubsubHandler(url, process, http.DefaultClient)
+	}
+	now := time.Unix(1700000000, 0).UTC()
+	tests := []struct {
+		handler func(url string) pubsubx.Handler
+		message interface{}
+		status  int
+		want    string
+		err     error
+	}{
+		{
+			handler: event,
+			message: models.ProcessEventModel{Type: models.ProcessEventStarted, Time: now, ChildPid: 7, Attempt: 1},
+			status:  http.StatusNoContent,
+			want:    `"event":{"type":"started","time":"2023-11-14T22:13:20Z","child_pid":7,"attempt":1`,
+		},
+		{
+			handler: sample,
+			message: models.ProcessSampleModel{Time: now, ChildPid: 7, Attempt: 2, Processes: 3},
+			status:  http.StatusOK,
+			want:    `"sample":{"time":"2023-11-14T22:13:20Z","child_pid":7,"attempt":2,"processes":3`,
+		},
+		{
+			handler: event,
+			message: models.ProcessEventModel{Type: models.ProcessEventStarted, Time: now},
+			status:  http.StatusBadRequest,
+			err:     httpx.ErrUnexpectedStatusCode,
+		},
+		{
+			handler: sample,
+			message: models.ProcessEventModel{Type: models.ProcessEventStarted, Time: now},
+			err:     pubsubx.ErrUnexpectedMessageType,
+		},
+	}
+	for i, test := range tests {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(test.status)
+		}))
+		err := test.handler(server.URL).Handle(context.Background(), test.message)
+		server.Close()
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: expected error %v, got %v", i, test.err, err)
+		}
+		if !strings.Contains(body, test.want) {
+			t.Errorf("test %d: expected body to contain %v, got %v", i, test.want, body)
+		}
+		if test.want != "" && !strings.Contains(body, `"process":{"pid":42`) {
+			t.Errorf("test %d: expected process in body, got %v", i, body)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/mainden/stdhttp/internal/models"
 	"github.com/mainden/stdhttp/pkg/httpx"
 	"github.com/mainden/stdhttp/pkg/logx"
+	"github.com/mainden/stdhttp/pkg/textx"
 )
 
 type postTextDebugHttpHandler struct {
@@ -27,28 +30,43 @@ func (h *postTextDebugHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	var data []byte
 	var err error
 	if err = httpx.AsData(r.Body, &data); err != nil {
-		logx.ErrorContext(ctx, "Failed to read body", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.String(), "headers", r.Header, "error", err)
+		logx.ErrorContext(ctx, "Failed to read body", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.Redacted(), "headers", httpx.RedactHeader(r.Header), "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	if len(data) == 0 {
-		logx.ErrorContext(ctx, "Request received without body", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.String(), "headers", r.Header)
+		logx.ErrorContext(ctx, "Request received without body", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.Redacted(), "headers", httpx.RedactHeader(r.Header))
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 	var body models.PostTextBody
 	if err = json.Unmarshal(data, &body); err != nil {
-		logx.ErrorContext(ctx, "Request received with invalid body format", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.String(), "headers", r.Header, "body", string(data))
+		logx.ErrorContext(ctx, "Request received with invalid body format", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.Redacted(), "headers", httpx.RedactHeader(r.Header), "body", string(data))
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	logx.DebugContext(ctx, "Request received", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.String(), "headers", r.Header, "body", body)
+	logx.DebugContext(ctx, "Request received", "remote_address", r.RemoteAddr, "method", r.Method, "url", r.URL.Redacted(), "headers", httpx.RedactHeader(r.Header), "body", body)
 	for _, item := range body.Items {
+		data := []byte(item.Message)
+		if item.Encoding == textx.EncodingBase64 {
+			if data, err = base64.StdEncoding.DecodeString(item.Message); err != nil {
+				logx.WarnContext(ctx, "Invalid base64 message", "source", item.Source, "error", err)
+				continue
+			}
+		}
+		if !item.Continued {
+			data = append(data, '\n')
+		}
 		switch item.Source {
 		case "stdout":
-			h.stdout.Write(append([]byte(item.Message), '\n'))
+			h.stdout.Write(data)
 		case "stderr":
-			h.stderr.Write(append([]byte(item.Message), '\n'))
+			h.stderr.Write(data)
 		default:
 			logx.WarnContext(ctx, "Unknown source", "source", item.Source)
 		}
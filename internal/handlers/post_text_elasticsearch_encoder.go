@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+var ErrElasticsearchRejected = errors.New("elasticsearch rejected documents")
+
+type postTextElasticsearchAction struct {
+	Create postTextElasticsearchActionMeta `json:"create"`
+}
+
+type postTextElasticsearchActionMeta struct {
+	Index string `json:"_index"`
+	Id    string `json:"_id,omitempty"`
+}
+
+type postTextElasticsearchDocument struct {
+	Timestamp string                      `json:"@timestamp"`
+	Message   string                      `json:"message"`
+	Source    string                      `json:"source"`
+	Seq       uint64                      `json:"seq"`
+	Encoding  string                      `json:"encoding,omitempty"`
+	Continued bool                        `json:"continued,omitempty"`
+	Truncated bool                        `json:"truncated,omitempty"`
+	Process   *models.PostTextBodyProcess `json:"process,omitempty"`
+}
+
+type postTextElasticsearchResponse struct {
+	Errors bool                                           `json:"errors"`
+	Items  []map[string]postTextElasticsearchResponseItem `json:"items"`
+}
+
+type postTextElasticsearchResponseItem struct {
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+type postTextElasticsearchEncoder struct {
+	index string
+}
+
+func (e *postTextElasticsearchEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, item := range body.Items {
+		// Documents get stable ids, so a batch sent again does not create duplicates
+		action := postTextElasticsearchAction{Create: postTextElasticsearchActionMeta{Index: e.index, Id: makePostTextElasticsearchId(body.Process, item)}}
+		timestamp := item.Time
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		document := postTextElasticsearchDocument{
+			Timestamp: timestamp.Format(time.RFC3339Nano),
+			Message:   item.Message,
+			Source:    item.Source,
+			Seq:       item.Seq,
+			Encoding:  item.Encoding,
+			Continued: item.Continued,
+			Truncated: item.Truncated,
+			Process:   body.Process,
+		}
+		if err := encoder.Encode(&action); err != nil {
+			return "", nil, err
+		}
+		if err := encoder.Encode(&document); err != nil {
+			return "", nil, err
+		}
+	}
+	return "application/x-ndjson", buffer.Bytes(), nil
+}
+
+func makePostTextElasticsearchId(process *models.PostTextBodyProcess, item models.PostTextBodyItem) string {
+	if item.Seq == 0 || item.Time.IsZero() {
+		return ""
+	}
+	id := strconv.FormatUint(item.Seq, 10) + "-" + strconv.FormatInt(item.Time.UnixNano(), 10)
+	if process == nil {
+		return id
+	}
+	id = strconv.Itoa(process.Pid) + "-" + id
+	if process.Hostname != "" {
+		id = process.Hostname + "-" + id
+	}
+	return id
+}
+
+func (e *postTextElasticsearchEncoder) Decode(ctx context.Context, body io.ReadCloser) error {
+	var response postTextElasticsearchResponse
+	if err := httpx.AsJson(body, &response); err != nil {
+		return err
+	}
+	if !response.Errors {
+		return nil
+	}
+	failed, retryable := 0, 0
+	var first json.RawMessage
+	for _, item := range response.Items {
+		for _, result := range item {
+			if len(result.Error) == 0 {
+				continue
+			}
+			if failed == 0 {
+				first = result.Error
+			}
+			failed++
+			if result.Status == http.StatusTooManyRequests || result.Status >= 500 {
+				retryable++
+			}
+		}
+	}
+	if retryable > 0 {
+		return fmt.Errorf("%w (%d of %d documents): %s", ErrElasticsearchRejected, failed, len(response.Items), first)
+	}
+	logx.WarnContext(ctx, "Elasticsearch rejected documents", "index", e.index, "failed", failed, "total", len(response.Items), "error", string(first))
+	return nil
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/textx"
+)
+
+type PostTextEncoder interface {
+	Encode(body models.PostTextBody) (contentType string, data []byte, err error)
+}
+
+type postTextResponseDecoder interface {
+	Decode(ctx context.Context, body io.ReadCloser) error
+}
+
+func NewPostTextEncoder(format string) (PostTextEncoder, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return &postTextJsonEncoder{}, nil
+	case "ndjson":
+		return &postTextNdjsonEncoder{}, nil
+	case "text":
+		return &postTextPlainEncoder{}, nil
+	case "cloudevents":
+		return &postTextCloudEventsEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format '%v'", format)
+	}
+}
+
+type postTextJsonEncoder struct{}
+
+func (e *postTextJsonEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	data, err := json.Marshal(&body)
+	return "application/json", data, err
+}
+
+type postTextNdjsonItem struct {
+	models.PostTextBodyItem
+	Process *models.PostTextBodyProcess `json:"process,omitempty"`
+}
+
+type postTextNdjsonEncoder struct{}
+
+func (e *postTextNdjsonEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, item := range body.Items {
+		if err := encoder.Encode(postTextNdjsonItem{PostTextBodyItem: item, Process: body.Process}); err != nil {
+			return "", nil, err
+		}
+	}
+	return "application/x-ndjson", buffer.Bytes(), nil
+}
+
+type postTextPlainEncoder struct{}
+
+func (e *postTextPlainEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	var buffer bytes.Buffer
+	for _, item := range body.Items {
+		data := []byte(item.Message)
+		if item.Encoding == textx.EncodingBase64 {
+			// Binary lines are written as they were read, the marker shows where they were cut
+			if decoded, err := base64.StdEncoding.DecodeString(item.Message); err == nil {
+				data = decoded
+				if item.Truncated {
+					data = append(data, textx.TruncatedMarker...)
+				}
+			}
+		}
+		buffer.Write(data)
+		if !item.Continued {
+			buffer.WriteByte('\n')
+		}
+	}
+	return "text/plain; charset=utf-8", buffer.Bytes(), nil
+}
+
+type postTextCloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	Id              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	Type            string                 `json:"type"`
+	Subject         string                 `json:"subject,omitempty"`
+	Time            string                 `json:"time,omitempty"`
+	DataContentType string                 `json:"datacontenttype"`
+	Data            postTextCloudEventData `json:"data"`
+}
+
+type postTextCloudEventData struct {
+	Message   string                      `json:"message"`
+	Seq       uint64                      `json:"seq"`
+	Encoding  string                      `json:"encoding,omitempty"`
+	Continued bool                        `json:"continued,omitempty"`
+	Truncated bool                        `json:"truncated,omitempty"`
+	Process   *models.PostTextBodyProcess `json:"process,omitempty"`
+}
+
+type postTextCloudEventsEncoder struct{}
+
+func makePostTextCloudEventSource(process *models.PostTextBodyProcess) string {
+	if process == nil {
+		return "/stdhttp"
+	}
+	if process.Hostname != "" {
+		return "//" + process.Hostname + "/stdhttp/" + strconv.Itoa(process.Pid)
+	}
+	return "/stdhttp/" + strconv.Itoa(process.Pid)
+}
+
+func makePostTextCloudEvent(process *models.PostTextBodyProcess, item models.PostTextBodyItem) postTextCloudEvent {
+	source := makePostTextCloudEventSource(process)
+	event := postTextCloudEvent{
+		SpecVersion:     "1.0",
+		Id:              source + "/" + strconv.FormatUint(item.Seq, 10) + "/" + strconv.FormatInt(item.Time.UnixNano(), 10),
+		Source:          source,
+		Type:            "com.github.mainden.stdhttp.line",
+		Subject:         item.Source,
+		DataContentType: "application/json",
+		Data: postTextCloudEventData{
+			Message:   item.Message,
+			Seq:       item.Seq,
+			Encoding:  item.Encoding,
+			Continued: item.Continued,
+			Truncated: item.Truncated,
+			Process:   process,
+		},
+	}
+	if !item.Time.IsZero() {
+		event.Time = item.Time.Format(time.RFC3339Nano)
+	}
+	return event
+}
+
+func (e *postTextCloudEventsEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	if len(body.Items) == 1 {
+		data, err := json.Marshal(makePostTextCloudEvent(body.Process, body.Items[0]))
+		return "application/cloudevents+json", data, err
+	}
+	events := make([]postTextCloudEvent, 0, len(body.Items))
+	for _, item := range body.Items {
+		events = append(events, makePostTextCloudEvent(body.Process, item))
+	}
+	data, err := json.Marshal(events)
+	return "application/cloudevents-batch+json", data, err
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/textx"
+)
+
+func postTextEncoderBody(messages ...string) models.PostTextBody {
+	process := &models.PostTextBodyProcess{Pid: 42, CommandName: "echo", Hostname: "host"}
+	var items []models.PostTextBodyItem
+	for i, message := range messages {
+		items = append(items, models.MakePostTextBodyItem("stdout", message, time.Unix(1700000000, int64(i)).UTC(), uint64(i+1)))
+	}
+	return models.MakePostTextBody(process, items...)
+}
+
+func postTextEncoderFlaggedBody() models.PostTextBody {
+	body := postTextEncoderBody("aGk=", "par", "tial")
+	body.Items[0].Encoding = textx.EncodingBase64
+	body.Items[0].Truncated = true
+	body.Items[1].Continued = true
+	return body
+}
+
+func TestPostTextEncoder(t *testing.T) {
+	tests := []struct {
+		format      string
+		body        models.PostTextBody
+		contentType string
+		want        string
+	}{
+		{
+			format:      "json",
+			body:        postTextEncoderBody("a"),
+			contentType: "application/json",
+			want:        `{"process":{"pid":42,"command_name":"echo","hostname":"host"},"items":[{"source":"stdout","message":"a","time":"2023-11-14T22:13:20Z","seq":1}]}`,
+		},
+		{
+			format:      "ndjson",
+			body:        postTextEncoderBody("a", "b"),
+			contentType: "application/x-ndjson",
+			want: `{"source":"stdout","message":"a","time":"2023-11-14T22:13:20Z","seq":1,"process":{"pid":42,"command_name":"echo","hostname":"host"}}` + "\n" +
+				`{"source":"stdout","message":"b","time":"2023-11-14T22:13:20.000000001Z","seq":2,"process":{"pid":42,"command_name":"echo","hostname":"host"}}` + "\n",
+		},
+		{
+			format:      "text",
+			body:        postTextEncoderBody("a", "b"),
+			contentType: "text/plain; charset=utf-8",
+			want:        "a\nb\n",
+		},
+		{
+			format:      "text",
+			body:        postTextEncoderFlaggedBody(),
+			contentType: "text/plain; charset=utf-8",
+			want:        "hi" + textx.TruncatedMarker + "\npartial\n",
+		},
+		{
+			format:      "ndjson",
+			body:        postTextEncoderFlaggedBody(),
+			contentType: "application/x-ndjson",
+			want: `{"source":"stdout","message":"aGk=","time":"2023-11-14T22:13:20Z","seq":1,"encoding":"base64","truncated":true,"process":{"pid":42,"command_name":"echo","hostname":"host"}}` + "\n" +
+				`{"source":"stdout","message":"par","time":"2023-11-14T22:13:20.000000001Z","seq":2,"continued":true,"process":{"pid":42,"command_name":"echo","hostname":"host"}}` + "\n" +
+				`{"source":"stdout","message":"tial","time":"2023-11-14T22:13:20.000000002Z","seq":3,"process":{"pid":42,"command_name":"echo","hostname":"host"}}` + "\n",
+		},
+		{
+			format:      "text",
+			body:        postTextEncoderBody(),
+			contentType: "text/plain; charset=utf-8",
+			want:        "",
+		},
+	}
+	for _, test := range tests {
+		encoder, err := NewPostTextEncoder(test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentType, data, err := encoder.Encode(test.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != test.contentType {
+			t.Errorf("format %v: expected content type %v, got %v", test.format, test.contentType, contentType)
+		}
+		if string(data) != test.want {
+			t.Errorf("format %v: expected %v, got %v", test.format, test.want, string(data))
+		}
+	}
+}
+
+func TestPostTextEncoderUnknownFormat(t *testing.T) {
+	if _, err := NewPostTextEncoder("xml"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestPostTextCloudEventsEncoder(t *testing.T) {
+	tests := []struct {
+		messages    []string
+		contentType string
+		events      int
+	}{
+		{[]string{"a"}, "application/cloudevents+json", 1},
+		{[]string{"a", "b"}, "application/cloudevents-batch+json", 2},
+		{[]string{"a", "b", "c"}, "application/cloudevents-batch+json", 3},
+	}
+	for _, test := range tests {
+		contentType, data, err := (&postTextCloudEventsEncoder{}).Encode(postTextEncoderBody(test.messages...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != test.contentType {
+			t.Errorf("%d items: expected content type %v, got %v", len(test.messages), test.contentType, contentType)
+		}
+		var events []postTextCloudEvent
+		if strings.HasPrefix(string(data), "[") {
+			err = json.Unmarshal(data, &events)
+		} else {
+			var event postTextCloudEvent
+			err = json.Unmarshal(data, &event)
+			events = append(events, event)
+		}
+		if err != nil {
+			t.Fatalf("%d items: %v", len(test.messages), err)
+		}
+		if len(events) != test.events {
+			t.Fatalf("%d items: expected %d events, got %d", len(test.messages), test.events, len(events))
+		}
+		for i, event := range events {
+			if event.SpecVersion != "1.0" || event.Type != "com.github.mainden.stdhttp.line" || event.Subject != "stdout" {
+				t.Errorf("event %d: unexpected attributes %+v", i, event)
+			}
+			if event.Source != "//host/stdhttp/42" {
+				t.Errorf("event %d: expected source //host/stdhttp/42, got %v", i, event.Source)
+			}
+			if event.Data.Message != test.messages[i] || event.Data.Seq != uint64(i+1) {
+				t.Errorf("event %d: expected message %v, got %v", i, test.messages[i], event.Data.Message)
+			}
+		}
+	}
+}
+
+func TestPostTextCloudEventsEncoderFlags(t *testing.T) {
+	_, data, err := (&postTextCloudEventsEncoder{}).Encode(postTextEncoderFlaggedBody())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []postTextCloudEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if data := events[0].Data; data.Encoding != textx.EncodingBase64 || !data.Truncated || data.Continued {
+		t.Errorf("event 0: unexpected data %+v", data)
+	}
+	if data := events[1].Data; data.Encoding != "" || data.Truncated || !data.Continued {
+		t.Errorf("event 1: unexpected data %+v", data)
+	}
+	if strings.Contains(string(data), `"continued":false`) || strings.Contains(string(data), `"encoding":""`) {
+		t.Errorf("expected unset flags to be omitted, got %s", data)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+)
+
+type postTextLokiBody struct {
+	Streams []postTextLokiStream `json:"streams"`
+}
+
+type postTextLokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]any           `json:"values"`
+}
+
+type postTextLokiEncoder struct{}
+
+func makePostTextLokiLabels(process *models.PostTextBodyProcess, source string) map[string]string {
+	labels := map[string]string{
+		"job":    "stdhttp",
+		"source": source,
+	}
+	if process == nil {
+		return labels
+	}
+	if process.ClientName != "" {
+		labels["client_name"] = process.ClientName
+	}
+	if process.CommandName != "" {
+		labels["command"] = filepath.Base(process.CommandName)
+	}
+	if process.Hostname != "" {
+		labels["hostname"] = process.Hostname
+	}
+	return labels
+}
+
+func makePostTextLokiMetadata(item models.PostTextBodyItem) map[string]string {
+	metadata := make(map[string]string)
+	if item.Encoding != "" {
+		metadata["encoding"] = item.Encoding
+	}
+	if item.Continued {
+		metadata["continued"] = "true"
+	}
+	if item.Truncated {
+		metadata["truncated"] = "true"
+	}
+	return metadata
+}
+
+func (e *postTextLokiEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	var streams []postTextLokiStream
+	indexes := make(map[string]int)
+	for _, item := range body.Items {
+		index, ok := indexes[item.Source]
+		if !ok {
+			index = len(streams)
+			indexes[item.Source] = index
+			streams = append(streams, postTextLokiStream{Stream: makePostTextLokiLabels(body.Process, item.Source)})
+		}
+		timestamp := item.Time
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		value := []any{strconv.FormatInt(timestamp.UnixNano(), 10), item.Message}
+		// Structured metadata is only sent when needed, so that plain lines stay accepted by older servers
+		if metadata := makePostTextLokiMetadata(item); len(metadata) > 0 {
+			value = append(value, metadata)
+		}
+		streams[index].Values = append(streams[index].Values, value)
+	}
+	data, err := json.Marshal(postTextLokiBody{Streams: streams})
+	return "application/json", data, err
+}
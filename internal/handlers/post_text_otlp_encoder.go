@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+)
+
+const (
+	otlpSeverityInfo  = 9
+	otlpSeverityError = 17
+)
+
+type otlpLogsData struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+func otlpString(value string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &value}
+}
+
+func otlpBool(value bool) otlpAnyValue {
+	return otlpAnyValue{BoolValue: &value}
+}
+
+func otlpInt(value int64) otlpAnyValue {
+	s := strconv.FormatInt(value, 10)
+	return otlpAnyValue{IntValue: &s}
+}
+
+func otlpStrings(values []string) otlpAnyValue {
+	array := &otlpArrayValue{Values: make([]otlpAnyValue, 0, len(values))}
+	for _, value := range values {
+		array.Values = append(array.Values, otlpString(value))
+	}
+	return otlpAnyValue{ArrayValue: array}
+}
+
+func makeOtlpResource(process *models.PostTextBodyProcess) otlpResource {
+	serviceName := "stdhttp"
+	if process == nil {
+		return otlpResource{Attributes: []otlpKeyValue{{Key: "service.name", Value: otlpString(serviceName)}}}
+	}
+	switch {
+	case process.ClientName != "":
+		serviceName = process.ClientName
+	case process.CommandName != "":
+		serviceName = filepath.Base(process.CommandName)
+	}
+	attributes := []otlpKeyValue{
+		{Key: "service.name", Value: otlpString(serviceName)},
+		{Key: "process.pid", Value: otlpInt(int64(process.Pid))},
+	}
+	if process.CommandName != "" {
+		attributes = append(attributes,
+			otlpKeyValue{Key: "process.executable.name", Value: otlpString(filepath.Base(process.CommandName))},
+			otlpKeyValue{Key: "process.command_args", Value: otlpStrings(append([]string{process.CommandName}, process.CommandArgs...))},
+		)
+	}
+	if process.Hostname != "" {
+		attributes = append(attributes, otlpKeyValue{Key: "host.name", Value: otlpString(process.Hostname)})
+	}
+	return otlpResource{Attributes: attributes}
+}
+
+type postTextOtlpEncoder struct{}
+
+func (e *postTextOtlpEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	records := make([]otlpLogRecord, 0, len(body.Items))
+	for _, item := range body.Items {
+		severityNumber, severityText := otlpSeverityInfo, "INFO"
+		if item.Source == "stderr" {
+			severityNumber, severityText = otlpSeverityError, "ERROR"
+		}
+		timestamp := observed
+		if !item.Time.IsZero() {
+			timestamp = strconv.FormatInt(item.Time.UnixNano(), 10)
+		}
+		attributes := []otlpKeyValue{
+			{Key: "log.iostream", Value: otlpString(item.Source)},
+			{Key: "stdhttp.seq", Value: otlpInt(int64(item.Seq))},
+		}
+		if item.Encoding != "" {
+			attributes = append(attributes, otlpKeyValue{Key: "stdhttp.encoding", Value: otlpString(item.Encoding)})
+		}
+		if item.Continued {
+			attributes = append(attributes, otlpKeyValue{Key: "stdhttp.continued", Value: otlpBool(true)})
+		}
+		if item.Truncated {
+			attributes = append(attributes, otlpKeyValue{Key: "stdhttp.truncated", Value: otlpBool(true)})
+		}
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:         timestamp,
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       severityNumber,
+			SeverityText:         severityText,
+			Body:                 otlpString(item.Message),
+			Attributes:           attributes,
+		})
+	}
+	data, err := json.Marshal(otlpLogsData{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: makeOtlpResource(body.Process),
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "stdhttp"},
+				LogRecords: records,
+			}},
+		}},
+	})
+	return "application/json", data, err
+}
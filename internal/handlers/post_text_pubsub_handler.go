@@ -9,41 +9,95 @@ import (
 	"github.com/mainden/stdhttp/pkg/httpx"
 	"github.com/mainden/stdhttp/pkg/logx"
 	"github.com/mainden/stdhttp/pkg/pubsubx"
+	"github.com/mainden/stdhttp/pkg/textx"
 )
 
 type postTextPubsubHandler struct {
-	url    string
-	source string
+	url     string
+	source  string
+	process *models.PostTextBodyProcess
+	encoder PostTextEncoder
+	client  httpx.HttpClient
 }
 
-func NewPostTextPubsubHandler(url string, source string) *postTextPubsubHandler {
+func NewPostTextPubsubHandler(url string, source string, process *models.PostTextBodyProcess, encoder PostTextEncoder, client httpx.HttpClient) *postTextPubsubHandler {
+	if encoder == nil {
+		encoder = &postTextJsonEncoder{}
+	}
 	return &postTextPubsubHandler{
-		url:    url,
-		source: source,
+		url:     url,
+		source:  source,
+		process: process,
+		encoder: encoder,
+		client:  client,
 	}
 }
 
-func (h *postTextPubsubHandler) Handle(ctx context.Context, message interface{}) (err error) {
-	ctx = logx.WithName(ctx, "post_text_pubsub_handler")
-	var body models.PostTextBody
+func makePostTextBodyItems(source string, lines ...textx.Line) []models.PostTextBodyItem {
+	items := make([]models.PostTextBodyItem, 0, len(lines))
+	for _, line := range lines {
+		item := models.MakePostTextBodyItem(source, line.Text, line.Time, line.Seq)
+		item.Encoding = line.Encoding
+		item.Continued = line.Continued
+		item.Truncated = line.Truncated
+		items = append(items, item)
+	}
+	return items
+}
+
+func makePostTextBody(source string, process *models.PostTextBodyProcess, message interface{}) (models.PostTextBody, error) {
 	switch message := message.(type) {
 	case string:
-		body = models.MakePostTextBody(h.source, message)
+		return models.MakePostTextBody(process, makePostTextBodyItems(source, textx.MakeLine(message))...), nil
 	case []string:
-		body = models.MakePostTextBody(h.source, message...)
+		var lines []textx.Line
+		for _, message := range message {
+			lines = append(lines, textx.MakeLine(message))
+		}
+		return models.MakePostTextBody(process, makePostTextBodyItems(source, lines...)...), nil
+	case textx.Line:
+		return models.MakePostTextBody(process, makePostTextBodyItems(source, message)...), nil
+	case []textx.Line:
+		return models.MakePostTextBody(process, makePostTextBodyItems(source, message...)...), nil
 	default:
-		return fmt.Errorf("%w (%T)", pubsubx.ErrUnexpectedMessageType, message)
+		return models.PostTextBody{}, fmt.Errorf("%w (%T)", pubsubx.ErrUnexpectedMessageType, message)
+	}
+}
+
+func (h *postTextPubsubHandler) Handle(ctx context.Context, message interface{}) (err error) {
+	ctx = logx.WithName(ctx, "post_text_pubsub_handler")
+	body, err := makePostTextBody(h.source, h.process, message)
+	if err != nil {
+		return err
 	}
 	if len(body.Items) == 0 {
 		logx.WarnContext(ctx, "No items to post")
 		return nil
 	}
+	return h.Post(ctx, body)
+}
+
+func (h *postTextPubsubHandler) Post(ctx context.Context, body models.PostTextBody) (err error) {
+	if h.client != nil {
+		ctx = httpx.WithHttpClient(ctx, h.client)
+	}
+	ctx = httpx.WithRetryable(ctx, true)
+	contentType, data, err := h.encoder.Encode(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
 	var resp *http.Response
-	if resp, err = httpx.DoJson(ctx, http.MethodPost, h.url, &body); err != nil {
+	if resp, err = httpx.DoData(ctx, http.MethodPost, h.url, contentType, data); err != nil {
 		return err
 	}
-	if err = httpx.AsNothing(resp.Body); err != nil {
-		return err
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if err = httpx.AsNothing(resp.Body); err != nil {
+			return err
+		}
+		return httpx.MakeErrorUnexpectedStatusCode(resp.StatusCode)
+	}
+	if decoder, ok := h.encoder.(postTextResponseDecoder); ok {
+		return decoder.Decode(ctx, resp.Body)
 	}
-	return nil
+	return httpx.AsNothing(resp.Body)
 }
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	PostTextSinkHttp          = "http"
+	PostTextSinkLoki          = "loki"
+	PostTextSinkElasticsearch = "es"
+	PostTextSinkSplunk        = "splunk"
+	PostTextSinkOtlp          = "otlp"
+)
+
+type PostTextSinkOptions struct {
+	Format             string
+	ElasticsearchIndex string
+	SplunkSourcetype   string
+	SplunkHost         string
+}
+
+var postTextSinkPaths = map[string]string{
+	PostTextSinkLoki:          "/loki/api/v1/push",
+	PostTextSinkElasticsearch: "/_bulk",
+	PostTextSinkSplunk:        "/services/collector/event",
+	PostTextSinkOtlp:          "/v1/logs",
+}
+
+func ParsePostTextSink(rawURL string) (sink string, targetURL string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse url: %w", err)
+	}
+	sink, scheme, ok := strings.Cut(u.Scheme, "+")
+	if !ok {
+		return PostTextSinkHttp, rawURL, nil
+	}
+	path, ok := postTextSinkPaths[sink]
+	if !ok {
+		return "", "", fmt.Errorf("unknown sink '%v'", sink)
+	}
+	u.Scheme = scheme
+	if u.Path == "" || u.Path == "/" {
+		u.Path = path
+	}
+	return sink, u.String(), nil
+}
+
+func NewPostTextSinkEncoder(sink string, options PostTextSinkOptions) (PostTextEncoder, error) {
+	switch sink {
+	case PostTextSinkHttp:
+		return NewPostTextEncoder(options.Format)
+	case PostTextSinkLoki:
+		return &postTextLokiEncoder{}, nil
+	case PostTextSinkElasticsearch:
+		if options.ElasticsearchIndex == "" {
+			return nil, fmt.Errorf("index is required for sink '%v'", sink)
+		}
+		return &postTextElasticsearchEncoder{index: options.ElasticsearchIndex}, nil
+	case PostTextSinkSplunk:
+		return &postTextSplunkEncoder{sourcetype: options.SplunkSourcetype, host: options.SplunkHost}, nil
+	case PostTextSinkOtlp:
+		return &postTextOtlpEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink '%v'", sink)
+	}
+}
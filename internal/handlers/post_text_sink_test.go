@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/textx"
+)
+
+func postTextSinkServer(t *testing.T, path string, contentType string, handle func(body []byte)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("expected path %v, got %v", path, r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != contentType {
+			t.Errorf("expected content type %v, got %v", contentType, r.Header.Get("Content-Type"))
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		handle(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+}
+
+func postTextSinkHandle(t *testing.T, rawURL string, options PostTextSinkOptions, lines ...textx.Line) {
+	sink, url, err := ParsePostTextSink(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoder, err := NewPostTextSinkEncoder(sink, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	process := &models.PostTextBodyProcess{Pid: 42, ClientName: "client", CommandName: "/bin/echo"}
+	handler := NewPostTextPubsubHandler(url, "stdout", process, encoder, http.DefaultClient)
+	if err := handler.Handle(context.Background(), lines); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostTextSinkLoki(t *testing.T) {
+	var body postTextLokiBody
+	server := postTextSinkServer(t, "/loki/api/v1/push", "application/json", func(data []byte) {
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Error(err)
+		}
+	})
+	defer server.Close()
+
+	now := time.Unix(1700000000, 5)
+	postTextSinkHandle(t, "loki+"+server.URL, PostTextSinkOptions{}, textx.Line{Text: "a", Time: now, Seq: 1}, textx.Line{Text: "b", Time: now, Seq: 2})
+	if len(body.Streams) != 1 {
+		t.Fatalf("expected 1 stream, got %v", len(body.Streams))
+	}
+	stream := body.Streams[0]
+	if stream.Stream["source"] != "stdout" || stream.Stream["client_name"] != "client" || stream.Stream["command"] != "echo" {
+		t.Errorf("unexpected labels %v", stream.Stream)
+	}
+	if len(stream.Values) != 2 || !reflect.DeepEqual(stream.Values[0], []any{"1700000000000000005", "a"}) || stream.Values[1][1] != "b" {
+		t.Errorf("unexpected values %v", stream.Values)
+	}
+}
+
+func TestPostTextSinkElasticsearch(t *testing.T) {
+	var lines []string
+	server := postTextSinkServer(t, "/_bulk", "application/x-ndjson", func(data []byte) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	})
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	postTextSinkHandle(t, "es+"+server.URL+"/", PostTextSinkOptions{ElasticsearchIndex: "logs"}, textx.Line{Text: "a", Time: now, Seq: 1}, textx.Line{Text: "b", Time: now, Seq: 2})
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %v", len(lines))
+	}
+	if lines[0] != `{"create":{"_index":"logs","_id":"42-1-1700000000000000000"}}` {
+		t.Errorf("unexpected action %v", lines[0])
+	}
+	var document postTextElasticsearchDocument
+	if err := json.Unmarshal([]byte(lines[3]), &document); err != nil {
+		t.Fatal(err)
+	}
+	if document.Message != "b" || document.Source != "stdout" || document.Seq != 2 || document.Process == nil {
+		t.Errorf("unexpected document %v", lines[3])
+	}
+}
+
+func TestPostTextElasticsearchDecode(t *testing.T) {
+	tests := []struct {
+		response string
+		rejected bool
+	}{
+		{`{"errors":false,"items":[{"create":{"status":201}}]}`, false},
+		{`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`, false},
+		{`{"errors":true,"items":[{"create":{"status":409,"error":{"type":"version_conflict_engine_exception"}}}]}`, false},
+		{`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`, true},
+		{`{"errors":true,"items":[{"create":{"status":503,"error":{"type":"unavailable_shards_exception"}}}]}`, true},
+	}
+	for _, test := range tests {
+		err := (&postTextElasticsearchEncoder{index: "logs"}).Decode(context.Background(), io.NopCloser(strings.NewReader(test.response)))
+		if got := errors.Is(err, ErrElasticsearchRejected); got != test.rejected {
+			t.Errorf("response %v: expected rejected %v, got %v", test.response, test.rejected, err)
+		}
+	}
+}
+
+func TestPostTextSinkSplunk(t *testing.T) {
+	var events []postTextSplunkEvent
+	server := postTextSinkServer(t, "/services/collector/event", "application/json", func(data []byte) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for decoder.More() {
+			var event postTextSplunkEvent
+			if err := decoder.Decode(&event); err != nil {
+				t.Error(err)
+				return
+			}
+			events = append(events, event)
+		}
+	})
+	defer server.Close()
+
+	postTextSinkHandle(t, "splunk+"+server.URL, PostTextSinkOptions{SplunkSourcetype: "app", SplunkHost: "host"}, textx.Line{Text: "a", Time: time.Unix(1700000000, 0), Seq: 1}, textx.Line{Text: "b", Time: time.Now(), Seq: 2})
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", len(events))
+	}
+	if events[0].Event != "a" || events[0].Time != 1700000000 || events[0].Sourcetype != "app" || events[0].Host != "host" || events[0].Fields.Pid != 42 {
+		t.Errorf("unexpected event %+v", events[0])
+	}
+}
+
+func TestPostTextSinkOtlp(t *testing.T) {
+	var logs otlpLogsData
+	server := postTextSinkServer(t, "/v1/logs", "application/json", func(data []byte) {
+		if err := json.Unmarshal(data, &logs); err != nil {
+			t.Error(err)
+		}
+	})
+	defer server.Close()
+
+	postTextSinkHandle(t, "otlp+"+server.URL, PostTextSinkOptions{}, textx.Line{Text: "a", Time: time.Unix(0, 7), Seq: 1})
+	if len(logs.ResourceLogs) != 1 || len(logs.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("unexpected logs %+v", logs)
+	}
+	resource := logs.ResourceLogs[0].Resource
+	if resource.Attributes[0].Key != "service.name" || *resource.Attributes[0].Value.StringValue != "client" {
+		t.Errorf("unexpected resource %+v", resource)
+	}
+	records := logs.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 || records[0].TimeUnixNano != "7" || records[0].SeverityText != "INFO" || *records[0].Body.StringValue != "a" {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestPostTextSinkFlags(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	lines := []textx.Line{
+		{Text: "aGk=", Time: now, Seq: 1, Encoding: textx.EncodingBase64, Truncated: true},
+		{Text: "par", Time: now, Seq: 2, Continued: true},
+	}
+	tests := []struct {
+		sink        string
+		path        string
+		contentType string
+		want        []string
+	}{
+		{
+			sink:        "loki",
+			path:        "/loki/api/v1/push",
+			contentType: "application/json",
+			want: []string{
+				`["1700000000000000000","aGk=",{"encoding":"base64","truncated":"true"}]`,
+				`["1700000000000000000","par",{"continued":"true"}]`,
+			},
+		},
+		{
+			sink:        "es",
+			path:        "/_bulk",
+			contentType: "application/x-ndjson",
+			want: []string{
+				`"seq":1,"encoding":"base64","truncated":true,"process"`,
+				`"seq":2,"continued":true,"process"`,
+			},
+		},
+		{
+			sink:        "splunk",
+			path:        "/services/collector/event",
+			contentType: "application/json",
+			want: []string{
+				`"fields":{"seq":1,"pid":42,"client_name":"client","command":"echo","encoding":"base64","truncated":true}`,
+				`"fields":{"seq":2,"pid":42,"client_name":"client","command":"echo","continued":true}`,
+			},
+		},
+		{
+			sink:        "otlp",
+			path:        "/v1/logs",
+			contentType: "application/json",
+			want: []string{
+				`{"key":"stdhttp.seq","value":{"intValue":"1"}},{"key":"stdhttp.encoding","value":{"stringValue":"base64"}},{"key":"stdhttp.truncated","value":{"boolValue":true}}]`,
+				`{"key":"stdhttp.seq","value":{"intValue":"2"}},{"key":"stdhttp.continued","value":{"boolValue":true}}]`,
+			},
+		},
+	}
+	for _, test := range tests {
+		var body string
+		server := postTextSinkServer(t, test.path, test.contentType, func(data []byte) {
+			body = string(data)
+		})
+		postTextSinkHandle(t, test.sink+"+"+server.URL+"/", PostTextSinkOptions{ElasticsearchIndex: "logs"}, lines...)
+		server.Close()
+		for _, want := range test.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("sink %v: expected body to contain %v, got %v", test.sink, want, body)
+			}
+		}
+	}
+}
+
+func TestParsePostTextSink(t *testing.T) {
+	if sink, url, err := ParsePostTextSink("http://localhost:8888/"); err != nil || sink != PostTextSinkHttp || url != "http://localhost:8888/" {
+		t.Errorf("unexpected sink %v %v %v", sink, url, err)
+	}
+	if sink, url, err := ParsePostTextSink("loki+https://loki:3100/custom"); err != nil || sink != PostTextSinkLoki || url != "https://loki:3100/custom" {
+		t.Errorf("unexpected sink %v %v %v", sink, url, err)
+	}
+	if _, _, err := ParsePostTextSink("unknown+http://localhost/"); err == nil || !strings.Contains(err.Error(), "unknown sink") {
+		t.Errorf("expected unknown sink error, got %v", err)
+	}
+}
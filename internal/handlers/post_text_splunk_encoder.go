@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+)
+
+type postTextSplunkEvent struct {
+	Time       float64                   `json:"time"`
+	Host       string                    `json:"host,omitempty"`
+	Source     string                    `json:"source"`
+	Sourcetype string                    `json:"sourcetype,omitempty"`
+	Event      string                    `json:"event"`
+	Fields     postTextSplunkEventFields `json:"fields"`
+}
+
+type postTextSplunkEventFields struct {
+	Seq         uint64 `json:"seq"`
+	Pid         int    `json:"pid,omitempty"`
+	ClientName  string `json:"client_name,omitempty"`
+	CommandName string `json:"command,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+	Continued   bool   `json:"continued,omitempty"`
+	Truncated   bool   `json:"truncated,omitempty"`
+}
+
+type postTextSplunkEncoder struct {
+	sourcetype string
+	host       string
+}
+
+func (e *postTextSplunkEncoder) Encode(body models.PostTextBody) (string, []byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	host := e.host
+	var fields postTextSplunkEventFields
+	if body.Process != nil {
+		if host == "" {
+			host = body.Process.Hostname
+		}
+		fields.Pid = body.Process.Pid
+		fields.ClientName = body.Process.ClientName
+		if body.Process.CommandName != "" {
+			fields.CommandName = filepath.Base(body.Process.CommandName)
+		}
+	}
+	for _, item := range body.Items {
+		timestamp := item.Time
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		fields.Seq = item.Seq
+		fields.Encoding = item.Encoding
+		fields.Continued = item.Continued
+		fields.Truncated = item.Truncated
+		event := postTextSplunkEvent{
+			Time:       float64(timestamp.UnixMicro()) / 1e6,
+			Host:       host,
+			Source:     item.Source,
+			Sourcetype: e.sourcetype,
+			Event:      item.Message,
+			Fields:     fields,
+		}
+		if err := encoder.Encode(&event); err != nil {
+			return "", nil, err
+		}
+	}
+	return "application/json", buffer.Bytes(), nil
+}
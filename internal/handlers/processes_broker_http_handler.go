@@ -20,6 +20,8 @@ type processesBroker interface {
 	Kill(ctx context.Context, pid int) (err error)
 	SendCommand(ctx context.Context, pid int, command string) (err error)
 	WaitCommand(ctx context.Context, pid int) (command string, err error)
+	Event(ctx context.Context, pid int, event models.ProcessEventModel) (err error)
+	Sample(ctx context.Context, pid int, sample models.ProcessSampleModel) (err error)
 	List(ctx context.Context) (processes models.ProcessModels, err error)
 }
 
@@ -47,6 +49,10 @@ func (handler *processesBrokerHttpHandler) ServeHTTP(w http.ResponseWriter, r *h
 		handler.sendCommand(w, r)
 	case "WaitCommand":
 		handler.waitCommand(w, r)
+	case "Event":
+		handler.event(w, r)
+	case "Sample":
+		handler.sample(w, r)
 	case "List":
 		handler.list(w, r)
 	default:
@@ -208,6 +214,59 @@ func (handler *processesBrokerHttpHandler) waitCommand(w http.ResponseWriter, r
 	fmt.Fprintf(handler.output, "process '%v': received command '%v'\n", pid, command)
 }
 
+func (handler *processesBrokerHttpHandler) event(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var body models.ProcessEventBodyItem
+	if err := httpx.AsJson(r.Body, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	event := body.ProcessEventModel()
+	if err := handler.processesBroker.Event(r.Context(), pid, event); err != nil {
+		if errors.Is(err, models.ErrProcessNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			fmt.Fprintf(handler.output, "process '%v': event '%v': process not found\n", pid, event.Type)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fmt.Fprintf(handler.output, "process '%v': event '%v': unexpected error\n", pid, event.Type)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	fmt.Fprintf(handler.output, "process '%v': event '%v'\n", pid, event.Type)
+}
+
+func (handler *processesBrokerHttpHandler) sample(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var body models.ProcessSampleBodyItem
+	if err := httpx.AsJson(r.Body, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.processesBroker.Sample(r.Context(), pid, body.ProcessSampleModel()); err != nil {
+		if errors.Is(err, models.ErrProcessNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			fmt.Fprintf(handler.output, "process '%v': sample: process not found\n", pid)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fmt.Fprintf(handler.output, "process '%v': sample: unexpected error\n", pid)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	fmt.Fprintf(handler.output, "process '%v': sample\n", pid)
+}
+
 func (handler *processesBrokerHttpHandler) list(w http.ResponseWriter, r *http.Request) {
 	if err := httpx.AsNothing(r.Body); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
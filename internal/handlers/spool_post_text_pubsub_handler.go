@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+const (
+	spoolRetryMinDelay = time.Second
+	spoolRetryMaxDelay = time.Minute
+)
+
+type textSpool interface {
+	Push(data []byte) (evicted int, err error)
+	Peek() (name string, data []byte, ok bool, err error)
+	Remove(name string) error
+	Len() int
+}
+
+type postTextPoster interface {
+	Post(ctx context.Context, body models.PostTextBody) error
+}
+
+type spoolPostTextPubsubHandler struct {
+	poster  postTextPoster
+	spool   textSpool
+	source  string
+	process *models.PostTextBodyProcess
+	notify  chan struct{}
+	closing chan struct{}
+	done    chan struct{}
+}
+
+func NewSpoolPostTextPubsubHandler(poster postTextPoster, spool textSpool, source string, process *models.PostTextBodyProcess) *spoolPostTextPubsubHandler {
+	h := &spoolPostTextPubsubHandler{
+		poster:  poster,
+		spool:   spool,
+		source:  source,
+		process: process,
+		notify:  make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go h.run(logx.WithName(context.Background(), "spool_post_text_pubsub_handler"))
+	return h
+}
+
+func (h *spoolPostTextPubsubHandler) Handle(ctx context.Context, message interface{}) (err error) {
+	ctx = logx.WithName(ctx, "spool_post_text_pubsub_handler")
+	body, err := makePostTextBody(h.source, h.process, message)
+	if err != nil {
+		return err
+	}
+	if len(body.Items) == 0 {
+		logx.WarnContext(ctx, "No items to post")
+		return nil
+	}
+	data, err := json.Marshal(&body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal body: %w", err)
+	}
+	evicted, err := h.spool.Push(data)
+	if evicted > 0 {
+		logx.WarnContext(ctx, "Spool size limit reached, oldest batches dropped", "source", h.source, "dropped", evicted)
+	}
+	if err != nil {
+		return err
+	}
+	select {
+	case h.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (h *spoolPostTextPubsubHandler) isClosing() bool {
+	select {
+	case <-h.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *spoolPostTextPubsubHandler) wait(delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-h.closing:
+	}
+}
+
+func (h *spoolPostTextPubsubHandler) run(ctx context.Context) {
+	defer close(h.done)
+	delay := spoolRetryMinDelay
+	for {
+		name, data, ok, err := h.spool.Peek()
+		if !ok {
+			if h.isClosing() {
+				return
+			}
+			select {
+			case <-h.notify:
+			case <-h.closing:
+			}
+			continue
+		}
+		ctx := logx.SetEvent(ctx, "spool")
+		var body models.PostTextBody
+		if err == nil {
+			err = json.Unmarshal(data, &body)
+		}
+		if err != nil {
+			logx.WarnContext(ctx, "Dropping unreadable spool entry", "source", h.source, "name", name, "error", err)
+			if !h.remove(ctx, name) {
+				return
+			}
+			continue
+		}
+		if err := h.poster.Post(ctx, body); httpx.IsPermanentError(err) {
+			logx.WarnContext(ctx, "Dropping spooled batch rejected by the server", "source", h.source, "name", name, "items", len(body.Items), "error", err)
+			if !h.remove(ctx, name) {
+				return
+			}
+			continue
+		} else if err != nil {
+			logx.DebugContext(ctx, "Failed to deliver spooled batch", "source", h.source, "name", name, "retry_delay", delay, "error", err)
+			if h.isClosing() {
+				logx.InfoContext(ctx, "Undelivered batches left in spool", "source", h.source, "count", h.spool.Len())
+				return
+			}
+			h.wait(delay)
+			delay = min(delay*2, spoolRetryMaxDelay)
+			continue
+		}
+		delay = spoolRetryMinDelay
+		if !h.remove(ctx, name) {
+			return
+		}
+	}
+}
+
+func (h *spoolPostTextPubsubHandler) remove(ctx context.Context, name string) bool {
+	// The entry must not be delivered again, so removal is retried until it succeeds
+	delay := spoolRetryMinDelay
+	for {
+		err := h.spool.Remove(name)
+		if err == nil {
+			return true
+		}
+		logx.ErrorContext(ctx, "Failed to remove spool entry", "source", h.source, "name", name, "retry_delay", delay, "error", err)
+		if h.isClosing() {
+			return false
+		}
+		h.wait(delay)
+		delay = min(delay*2, spoolRetryMaxDelay)
+	}
+}
+
+func (h *spoolPostTextPubsubHandler) Close() error {
+	close(h.closing)
+	<-h.done
+	return nil
+}
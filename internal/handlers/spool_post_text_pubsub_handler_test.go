@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainden/stdhttp/internal/models"
+	"github.com/mainden/stdhttp/pkg/httpx"
+)
+
+type memorySpool struct {
+	mutex        sync.Mutex
+	entries      [][]byte
+	next         int
+	removeErrors int
+}
+
+func (s *memorySpool) Push(data []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, data)
+	return 0, nil
+}
+
+func (s *memorySpool) Peek() (string, []byte, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.entries) == 0 {
+		return "", nil, false, nil
+	}
+	return strconv.Itoa(s.next), s.entries[0], true, nil
+}
+
+func (s *memorySpool) Remove(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.removeErrors > 0 {
+		s.removeErrors--
+		return errors.New("remove failed")
+	}
+	s.entries = s.entries[1:]
+	s.next++
+	return nil
+}
+
+func (s *memorySpool) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.entries)
+}
+
+type recordingPoster struct {
+	mutex    sync.Mutex
+	messages []string
+	errs     []error
+}
+
+func (p *recordingPoster) Post(ctx context.Context, body models.PostTextBody) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.messages = append(p.messages, body.Items[0].Message)
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func (p *recordingPoster) posted() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.messages...)
+}
+
+func awaitSpoolEmpty(t *testing.T, spool *memorySpool, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for spool.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected spool to be drained, %d entries left", spool.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSpoolPostTextPubsubHandlerDropsRejectedBatch(t *testing.T) {
+	spool := &memorySpool{}
+	poster := &recordingPoster{errs: []error{httpx.MakeErrorUnexpectedStatusCode(413)}}
+	h := NewSpoolPostTextPubsubHandler(poster, spool, "stdout", nil)
+	defer h.Close()
+	h.Handle(context.Background(), "rejected")
+	h.Handle(context.Background(), "accepted")
+	awaitSpoolEmpty(t, spool, time.Second)
+	if got := poster.posted(); len(got) != 2 || got[0] != "rejected" || got[1] != "accepted" {
+		t.Errorf("expected each batch posted once, got %q", got)
+	}
+}
+
+func TestSpoolPostTextPubsubHandlerRetriesRemove(t *testing.T) {
+	spool := &memorySpool{removeErrors: 1}
+	poster := &recordingPoster{}
+	h := NewSpoolPostTextPubsubHandler(poster, spool, "stdout", nil)
+	defer h.Close()
+	h.Handle(context.Background(), "first")
+	awaitSpoolEmpty(t, spool, 3*time.Second)
+	h.Handle(context.Background(), "second")
+	awaitSpoolEmpty(t, spool, time.Second)
+	if got := poster.posted(); len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("expected each batch posted once, got %q", got)
+	}
+}
@@ -1,27 +1,42 @@
 package models
 
+import "time"
+
 type PostTextBody struct {
-	Items []PostTextBodyItem `json:"items"`
+	Process *PostTextBodyProcess `json:"process,omitempty"`
+	Items   []PostTextBodyItem   `json:"items"`
+}
+
+type PostTextBodyProcess struct {
+	Pid         int      `json:"pid"`
+	ClientName  string   `json:"client_name,omitempty"`
+	CommandName string   `json:"command_name,omitempty"`
+	CommandArgs []string `json:"command_args,omitempty"`
+	Hostname    string   `json:"hostname,omitempty"`
 }
 
 type PostTextBodyItem struct {
-	Source  string `json:"source"`
-	Message string `json:"message"`
+	Source    string    `json:"source"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+	Seq       uint64    `json:"seq"`
+	Encoding  string    `json:"encoding,omitempty"`
+	Continued bool      `json:"continued,omitempty"`
+	Truncated bool      `json:"truncated,omitempty"`
 }
 
-func MakePostTextBodyItem(source, message string) PostTextBodyItem {
+func MakePostTextBodyItem(source string, message string, time time.Time, seq uint64) PostTextBodyItem {
 	return PostTextBodyItem{
 		Source:  source,
 		Message: message,
+		Time:    time,
+		Seq:     seq,
 	}
 }
 
-func MakePostTextBody(source string, messages ...string) PostTextBody {
-	var items []PostTextBodyItem
-	for _, message := range messages {
-		items = append(items, MakePostTextBodyItem(source, message))
-	}
+func MakePostTextBody(process *PostTextBodyProcess, items ...PostTextBodyItem) PostTextBody {
 	return PostTextBody{
-		Items: items,
+		Process: process,
+		Items:   items,
 	}
 }
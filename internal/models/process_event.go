@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ProcessEventType string
+
+const (
+	ProcessEventStarted     ProcessEventType = "started"
+	ProcessEventStartFailed ProcessEventType = "start_failed"
+	ProcessEventExited      ProcessEventType = "exited"
+	ProcessEventRestarting  ProcessEventType = "restarting"
+)
+
+type ProcessEventModel struct {
+	Type       ProcessEventType
+	Time       time.Time
+	ChildPid   int
+	Attempt    int
+	ExitCode   int
+	Signal     string
+	Duration   time.Duration
+	Delay      time.Duration
+	Error      string
+	MemoryPeak int64
+	OOMKilled  bool
+	Usage      *ProcessUsageModel
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+type ProcessEventBody struct {
+	Process *PostTextBodyProcess `json:"process,omitempty"`
+	Event   ProcessEventBodyItem `json:"event"`
+}
+
+type ProcessEventBodyItem struct {
+	Type       string                `json:"type"`
+	Time       time.Time             `json:"time"`
+	ChildPid   int                   `json:"child_pid,omitempty"`
+	Attempt    int                   `json:"attempt"`
+	ExitCode   *int                  `json:"exit_code,omitempty"`
+	Signal     string                `json:"signal,omitempty"`
+	DurationMs int64                 `json:"duration_ms,omitempty"`
+	DelayMs    int64                 `json:"delay_ms,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	MemoryPeak int64                 `json:"memory_peak,omitempty"`
+	OOMKilled  bool                  `json:"oom_killed,omitempty"`
+	Usage      *ProcessUsageBodyItem `json:"usage,omitempty"`
+}
+
+func (item ProcessEventBodyItem) ProcessEventModel() ProcessEventModel {
+	event := ProcessEventModel{
+		Type:       ProcessEventType(item.Type),
+		Time:       item.Time,
+		ChildPid:   item.ChildPid,
+		Attempt:    item.Attempt,
+		Signal:     item.Signal,
+		Duration:   time.Duration(item.DurationMs) * time.Millisecond,
+		Delay:      time.Duration(item.DelayMs) * time.Millisecond,
+		Error:      item.Error,
+		MemoryPeak: item.MemoryPeak,
+		OOMKilled:  item.OOMKilled,
+	}
+	if item.ExitCode != nil {
+		event.ExitCode = *item.ExitCode
+	}
+	if item.Usage != nil {
+		usage := item.Usage.ProcessUsageModel()
+		event.Usage = &usage
+	}
+	return event
+}
+
+func MakeProcessEventBodyItem(event ProcessEventModel) ProcessEventBodyItem {
+	item := ProcessEventBodyItem{
+		Type:       string(event.Type),
+		Time:       event.Time,
+		ChildPid:   event.ChildPid,
+		Attempt:    event.Attempt,
+		Signal:     event.Signal,
+		DurationMs: event.Duration.Milliseconds(),
+		DelayMs:    event.Delay.Milliseconds(),
+		Error:      event.Error,
+		MemoryPeak: event.MemoryPeak,
+		OOMKilled:  event.OOMKilled,
+	}
+	if event.Type == ProcessEventExited {
+		exitCode := event.ExitCode
+		item.ExitCode = &exitCode
+	}
+	if event.Usage != nil {
+		usage := MakeProcessUsageBodyItem(*event.Usage)
+		item.Usage = &usage
+	}
+	return item
+}
+
+func MakeProcessEventBody(process *PostTextBodyProcess, event ProcessEventModel) ProcessEventBody {
+	return ProcessEventBody{
+		Process: process,
+		Event:   MakeProcessEventBodyItem(event),
+	}
+}
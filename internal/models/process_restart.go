@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	ProcessRestartAlways = "always"
+	ProcessRestartNever  = "never"
+)
+
+type ProcessRestartModel struct {
+	Policy      string
+	MinDelay    time.Duration
+	MaxDelay    time.Duration
+	MaxRestarts int
+	Window      time.Duration
+	ResetAfter  time.Duration
+}
+
+func (restart ProcessRestartModel) Persistent() bool {
+	return restart.Policy != "" && restart.Policy != ProcessRestartNever
+}
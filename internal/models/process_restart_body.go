@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type ProcessRestartBodyItem struct {
+	Policy       string `json:"policy"`
+	MinDelayMs   int64  `json:"min_delay_ms"`
+	MaxDelayMs   int64  `json:"max_delay_ms"`
+	MaxRestarts  int    `json:"max_restarts"`
+	WindowMs     int64  `json:"window_ms"`
+	ResetAfterMs int64  `json:"reset_after_ms"`
+}
+
+func (item ProcessRestartBodyItem) ProcessRestartModel() ProcessRestartModel {
+	return ProcessRestartModel{
+		Policy:      item.Policy,
+		MinDelay:    time.Duration(item.MinDelayMs) * time.Millisecond,
+		MaxDelay:    time.Duration(item.MaxDelayMs) * time.Millisecond,
+		MaxRestarts: item.MaxRestarts,
+		Window:      time.Duration(item.WindowMs) * time.Millisecond,
+		ResetAfter:  time.Duration(item.ResetAfterMs) * time.Millisecond,
+	}
+}
+
+func MakeProcessRestartBodyItem(restart ProcessRestartModel) ProcessRestartBodyItem {
+	return ProcessRestartBodyItem{
+		Policy:       restart.Policy,
+		MinDelayMs:   restart.MinDelay.Milliseconds(),
+		MaxDelayMs:   restart.MaxDelay.Milliseconds(),
+		MaxRestarts:  restart.MaxRestarts,
+		WindowMs:     restart.Window.Milliseconds(),
+		ResetAfterMs: restart.ResetAfter.Milliseconds(),
+	}
+}
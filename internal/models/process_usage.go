@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ProcessUsageModel struct {
+	UserTime                   time.Duration
+	SystemTime                 time.Duration
+	MaxRSS                     int64
+	VoluntaryContextSwitches   int64
+	InvoluntaryContextSwitches int64
+}
+
+type ProcessSampleModel struct {
+	Time      time.Time
+	ChildPid  int
+	Attempt   int
+	Processes int
+	CPUTime   time.Duration
+	CPUUsage  float64
+	RSS       int64
+	OpenFDs   int
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+type ProcessUsageBodyItem struct {
+	UserTimeMs                 int64 `json:"user_time_ms"`
+	SystemTimeMs               int64 `json:"system_time_ms"`
+	MaxRSS                     int64 `json:"max_rss"`
+	VoluntaryContextSwitches   int64 `json:"voluntary_context_switches"`
+	InvoluntaryContextSwitches int64 `json:"involuntary_context_switches"`
+}
+
+func (item ProcessUsageBodyItem) ProcessUsageModel() ProcessUsageModel {
+	return ProcessUsageModel{
+		UserTime:                   time.Duration(item.UserTimeMs) * time.Millisecond,
+		SystemTime:                 time.Duration(item.SystemTimeMs) * time.Millisecond,
+		MaxRSS:                     item.MaxRSS,
+		VoluntaryContextSwitches:   item.VoluntaryContextSwitches,
+		InvoluntaryContextSwitches: item.InvoluntaryContextSwitches,
+	}
+}
+
+func MakeProcessUsageBodyItem(usage ProcessUsageModel) ProcessUsageBodyItem {
+	return ProcessUsageBodyItem{
+		UserTimeMs:                 usage.UserTime.Milliseconds(),
+		SystemTimeMs:               usage.SystemTime.Milliseconds(),
+		MaxRSS:                     usage.MaxRSS,
+		VoluntaryContextSwitches:   usage.VoluntaryContextSwitches,
+		InvoluntaryContextSwitches: usage.InvoluntaryContextSwitches,
+	}
+}
+
+type ProcessSampleBody struct {
+	Process *PostTextBodyProcess  `json:"process,omitempty"`
+	Sample  ProcessSampleBodyItem `json:"sample"`
+}
+
+type ProcessSampleBodyItem struct {
+	Time      time.Time `json:"time"`
+	ChildPid  int       `json:"child_pid"`
+	Attempt   int       `json:"attempt"`
+	Processes int       `json:"processes"`
+	CPUTimeMs int64     `json:"cpu_time_ms"`
+	CPUUsage  float64   `json:"cpu_usage"`
+	RSS       int64     `json:"rss"`
+	OpenFDs   int       `json:"open_fds"`
+}
+
+func (item ProcessSampleBodyItem) ProcessSampleModel() ProcessSampleModel {
+	return ProcessSampleModel{
+		Time:      item.Time,
+		ChildPid:  item.ChildPid,
+		Attempt:   item.Attempt,
+		Processes: item.Processes,
+		CPUTime:   time.Duration(item.CPUTimeMs) * time.Millisecond,
+		CPUUsage:  item.CPUUsage,
+		RSS:       item.RSS,
+		OpenFDs:   item.OpenFDs,
+	}
+}
+
+func MakeProcessSampleBodyItem(sample ProcessSampleModel) ProcessSampleBodyItem {
+	return ProcessSampleBodyItem{
+		Time:      sample.Time,
+		ChildPid:  sample.ChildPid,
+		Attempt:   sample.Attempt,
+		Processes: sample.Processes,
+		CPUTimeMs: sample.CPUTime.Milliseconds(),
+		CPUUsage:  sample.CPUUsage,
+		RSS:       sample.RSS,
+		OpenFDs:   sample.OpenFDs,
+	}
+}
+
+func MakeProcessSampleBody(process *PostTextBodyProcess, sample ProcessSampleModel) ProcessSampleBody {
+	return ProcessSampleBody{
+		Process: process,
+		Sample:  MakeProcessSampleBodyItem(sample),
+	}
+}
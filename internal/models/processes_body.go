@@ -13,34 +13,63 @@ func (body ProcessesBody) ProcessModels() ProcessModels {
 }
 
 type ProcessesBodyItem struct {
-	Pid         int      `json:"pid"`
-	ClientName  string   `json:"client_name"`
-	CommandName string   `json:"command_name"`
-	CommandArgs []string `json:"command_args"`
-	Expired     bool     `json:"expired"`
-	Persistent  bool     `json:"persistent"`
+	Pid         int                    `json:"pid"`
+	ClientName  string                 `json:"client_name"`
+	CommandName string                 `json:"command_name"`
+	CommandArgs []string               `json:"command_args"`
+	Expired     bool                   `json:"expired"`
+	Persistent  bool                   `json:"persistent"`
+	Restart     ProcessRestartBodyItem `json:"restart"`
+	LastEvent   *ProcessEventBodyItem  `json:"last_event,omitempty"`
+	LastSample  *ProcessSampleBodyItem `json:"last_sample,omitempty"`
 }
 
 func (item ProcessesBodyItem) ProcessModel() ProcessModel {
-	return ProcessModel{
+	process := ProcessModel{
 		Pid:         item.Pid,
 		ClientName:  item.ClientName,
 		CommandName: item.CommandName,
 		CommandArgs: item.CommandArgs,
-		Persistent:  item.Persistent,
+		Restart:     item.Restart.ProcessRestartModel(),
 		Expired:     item.Expired,
 	}
+	if process.Restart.Policy == "" {
+		// Older clients only send the persistent flag
+		process.Restart.Policy = ProcessRestartNever
+		if item.Persistent {
+			process.Restart.Policy = ProcessRestartAlways
+		}
+	}
+	if item.LastEvent != nil {
+		event := item.LastEvent.ProcessEventModel()
+		process.LastEvent = &event
+	}
+	if item.LastSample != nil {
+		sample := item.LastSample.ProcessSampleModel()
+		process.LastSample = &sample
+	}
+	return process
 }
 
 func MakeProcessesBodyItem(process ProcessModel) ProcessesBodyItem {
-	return ProcessesBodyItem{
+	item := ProcessesBodyItem{
 		Pid:         process.Pid,
 		ClientName:  process.ClientName,
 		CommandName: process.CommandName,
 		CommandArgs: process.CommandArgs,
-		Persistent:  process.Persistent,
+		Persistent:  process.Restart.Persistent(),
+		Restart:     MakeProcessRestartBodyItem(process.Restart),
 		Expired:     process.Expired,
 	}
+	if process.LastEvent != nil {
+		event := MakeProcessEventBodyItem(*process.LastEvent)
+		item.LastEvent = &event
+	}
+	if process.LastSample != nil {
+		sample := MakeProcessSampleBodyItem(*process.LastSample)
+		item.LastSample = &sample
+	}
+	return item
 }
 
 func MakeProcessesBody(processes ...ProcessModel) ProcessesBody {
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProcessesBodyPersistent(t *testing.T) {
+	tests := []struct {
+		data       string
+		policy     string
+		persistent bool
+	}{
+		{`{"items":[{"pid":1,"persistent":true}]}`, ProcessRestartAlways, true},
+		{`{"items":[{"pid":1,"persistent":false}]}`, ProcessRestartNever, false},
+		{`{"items":[{"pid":1,"persistent":true,"restart":{"policy":"on-failure"}}]}`, "on-failure", true},
+		{`{"items":[{"pid":1,"restart":{"policy":"never"}}]}`, ProcessRestartNever, false},
+	}
+	for _, test := range tests {
+		var body ProcessesBody
+		if err := json.Unmarshal([]byte(test.data), &body); err != nil {
+			t.Fatal(err)
+		}
+		process := body.ProcessModels()[0]
+		if process.Restart.Policy != test.policy {
+			t.Errorf("body %v: expected policy %v, got %v", test.data, test.policy, process.Restart.Policy)
+		}
+		if item := MakeProcessesBodyItem(process); item.Persistent != test.persistent {
+			t.Errorf("body %v: expected persistent %v, got %v", test.data, test.persistent, item.Persistent)
+		}
+	}
+}
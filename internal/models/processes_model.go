@@ -17,8 +17,10 @@ type ProcessModel struct {
 	ClientName  string
 	CommandName string
 	CommandArgs []string
-	Persistent  bool
+	Restart     ProcessRestartModel
 	Expired     bool
+	LastEvent   *ProcessEventModel
+	LastSample  *ProcessSampleModel
 }
 
 type ProcessModels []ProcessModel
@@ -452,8 +452,10 @@ func AddParam(name string, format string) {
 }
 
 func (fs *FlagSet) setDefaults() error {
+	// Each source replaces the values of list flags set by the sources before it
 	for _, flag := range fs.envFlags {
 		if flag.Default != "" {
+			Reset(flag.Value)
 			if _, err := flag.Value.Parse(flag.Default); err != nil {
 				return fmt.Errorf("setting default: env '%v': %w", flag.Name, err)
 			}
@@ -461,11 +463,20 @@ func (fs *FlagSet) setDefaults() error {
 	}
 	for _, flag := range fs.optFlags {
 		if flag.Defaults != nil {
+			Reset(flag.Value)
 			if _, err := flag.Value.Parse(flag.Defaults...); err != nil {
 				return fmt.Errorf("setting default: option '%v': %w", flag.Name, err)
 			}
 		}
 	}
+	for _, flag := range fs.envFlags {
+		if value := os.Getenv(flag.Name); value != "" {
+			Reset(flag.Value)
+			if _, err := flag.Value.Parse(value); err != nil {
+				return fmt.Errorf("env '%v': %w", flag.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -581,6 +592,9 @@ func (fs *FlagSet) parse(args ...string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	for _, flag := range fs.optFlags {
+		Reset(flag.Value)
+	}
 	n, err := fs.parseOpts(args...)
 	if err != nil {
 		return n, err
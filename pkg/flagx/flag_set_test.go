@@ -0,0 +1,82 @@
+package flagx
+
+import (
+	"io"
+	"slices"
+	"testing"
+)
+
+func newTestFlagSet(value *string) *FlagSet {
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.SetPrefix("STDHTTP_TEST")
+	fs.SetOutput(io.Discard)
+	fs.AddOptEnvString("value", 'v', "VALUE", "Sets the value.", value, WithDefaults("default"))
+	return fs
+}
+
+func TestFlagSetEnvOrder(t *testing.T) {
+	tests := []struct {
+		env  string
+		args []string
+		want string
+	}{
+		{"", nil, "default"},
+		{"env", nil, "env"},
+		{"", []string{"--value", "cli"}, "cli"},
+		{"env", []string{"--value", "cli"}, "cli"},
+		{"env", []string{"-v", "cli"}, "cli"},
+	}
+	for _, test := range tests {
+		t.Setenv("STDHTTP_TEST_VALUE", test.env)
+		var value string
+		if _, err := newTestFlagSet(&value).Parse(test.args...); err != nil {
+			t.Fatalf("env %q, args %v: unexpected error: %v", test.env, test.args, err)
+		}
+		if value != test.want {
+			t.Errorf("env %q, args %v: expected %v, got %v", test.env, test.args, test.want, value)
+		}
+	}
+}
+
+func TestFlagSetEnvInvalid(t *testing.T) {
+	t.Setenv("STDHTTP_TEST_COUNT", "many")
+	var count int
+	fs := NewFlagSet("test", ContinueOnError)
+	fs.SetPrefix("STDHTTP_TEST")
+	fs.SetOutput(io.Discard)
+	fs.AddOptEnvInt("count", 'c', "COUNT", "Sets the count.", &count)
+	if _, err := fs.Parse(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestFlagSetStringListSources(t *testing.T) {
+	tests := []struct {
+		env  string
+		args []string
+		want []string
+	}{
+		{"", nil, []string{"a", "b"}},
+		{"c", nil, []string{"c"}},
+		{"c,d", nil, []string{"c", "d"}},
+		{"", []string{"--list", "e"}, []string{"e"}},
+		{"c", []string{"--list", "e", "--list", "f,g"}, []string{"e", "f", "g"}},
+		{"c", []string{"--both"}, []string{"x"}},
+		{"c", []string{"--both", "--list", "e"}, []string{"x", "e"}},
+	}
+	for _, test := range tests {
+		t.Setenv("STDHTTP_TEST_LIST", test.env)
+		var list []string
+		fs := NewFlagSet("test", ContinueOnError)
+		fs.SetPrefix("STDHTTP_TEST")
+		fs.SetOutput(io.Discard)
+		flag, _ := fs.AddOptEnvStringList("list", 'l', "ITEM", "Adds the item.", &list, ",", WithDefaults("a,b"))
+		fs.AddOpt("both", 0, "", "Adds the x item.", Args(flag.Value, "x"))
+		if _, err := fs.Parse(test.args...); err != nil {
+			t.Fatalf("env %q, args %v: unexpected error: %v", test.env, test.args, err)
+		}
+		if !slices.Equal(list, test.want) {
+			t.Errorf("env %q, args %v: expected %v, got %v", test.env, test.args, test.want, list)
+		}
+	}
+}
@@ -35,6 +35,12 @@ func IsInlined(v Value) bool {
 	return false
 }
 
+func Reset(v Value) {
+	if v, ok := v.(interface{ Reset() }); ok {
+		v.Reset()
+	}
+}
+
 func SelectValue(predicate bool, trueValue Value, falseValue Value) Value {
 	if predicate {
 		return trueValue
@@ -0,0 +1,54 @@
+package flagx
+
+import (
+	"errors"
+	"strconv"
+)
+
+type valueInt64 struct {
+	pointer *int64
+}
+
+func Int64(pointer *int64) *valueInt64 {
+	return &valueInt64{pointer: pointer}
+}
+
+func (value *valueInt64) Parse(args ...string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("missing argument")
+	}
+	n, err := strconv.ParseInt(args[0], 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	*value.pointer = n
+	return 1, nil
+}
+
+func (value *valueInt64) Format() string {
+	return strconv.FormatInt(*value.pointer, 10)
+}
+
+func (fs *FlagSet) AddOptInt64(name string, alias rune, params string, usage string, pointer *int64, wrappers ...Wrapper) *OptFlag {
+	return fs.AddOpt(name, alias, params, usage, Int64(pointer), wrappers...)
+}
+
+func (fs *FlagSet) AddEnvInt64(name string, params string, usage string, pointer *int64, wrappers ...Wrapper) *EnvFlag {
+	return fs.AddEnv(name, params, usage, Int64(pointer), wrappers...)
+}
+
+func (fs *FlagSet) AddOptEnvInt64(name string, alias rune, params string, usage string, pointer *int64, wrappers ...Wrapper) (*OptFlag, *EnvFlag) {
+	return fs.AddOptEnv(name, alias, params, usage, Int64(pointer), wrappers...)
+}
+
+func AddOptInt64(name string, alias rune, params string, usage string, pointer *int64, wrappers ...Wrapper) *OptFlag {
+	return CommandLine.AddOpt(name, alias, params, usage, Int64(pointer), wrappers...)
+}
+
+func AddEnvInt64(name string, params string, usage string, pointer *int64, wrappers ...Wrapper) *EnvFlag {
+	return CommandLine.AddEnv(name, params, usage, Int64(pointer), wrappers...)
+}
+
+func AddOptEnvInt64(name string, alias rune, params string, usage string, pointer *int64, wrappers ...Wrapper) (*OptFlag, *EnvFlag) {
+	return CommandLine.AddOptEnv(name, alias, params, usage, Int64(pointer), wrappers...)
+}
@@ -1,6 +1,9 @@
 package flagx
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 type valueString struct {
 	pointer *string
@@ -86,3 +89,61 @@ func AddEnvStringSlice(name string, params string, usage string, pointer *[]stri
 func AddOptEnvStringSlice(name string, alias rune, params string, usage string, pointer *[]string, wrappers ...Wrapper) (*OptFlag, *EnvFlag) {
 	return CommandLine.AddOptEnv(name, alias, params, usage, StringSlice(pointer), wrappers...)
 }
+
+type valueStringList struct {
+	pointer   *[]string
+	separator string
+	reset     bool
+}
+
+func StringList(pointer *[]string, separator string) *valueStringList {
+	return &valueStringList{pointer: pointer, separator: separator}
+}
+
+func (value *valueStringList) Parse(args ...string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("missing argument")
+	}
+	if value.reset {
+		*value.pointer = nil
+		value.reset = false
+	}
+	for _, arg := range strings.Split(args[0], value.separator) {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			*value.pointer = append(*value.pointer, arg)
+		}
+	}
+	return 1, nil
+}
+
+func (value *valueStringList) Format() []string {
+	return *value.pointer
+}
+
+func (value *valueStringList) Reset() {
+	value.reset = true
+}
+
+func (fs *FlagSet) AddOptStringList(name string, alias rune, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) *OptFlag {
+	return fs.AddOpt(name, alias, params, usage, StringList(pointer, separator), wrappers...)
+}
+
+func (fs *FlagSet) AddEnvStringList(name string, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) *EnvFlag {
+	return fs.AddEnv(name, params, usage, StringList(pointer, separator), wrappers...)
+}
+
+func (fs *FlagSet) AddOptEnvStringList(name string, alias rune, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) (*OptFlag, *EnvFlag) {
+	return fs.AddOptEnv(name, alias, params, usage, StringList(pointer, separator), wrappers...)
+}
+
+func AddOptStringList(name string, alias rune, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) *OptFlag {
+	return CommandLine.AddOpt(name, alias, params, usage, StringList(pointer, separator), wrappers...)
+}
+
+func AddEnvStringList(name string, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) *EnvFlag {
+	return CommandLine.AddEnv(name, params, usage, StringList(pointer, separator), wrappers...)
+}
+
+func AddOptEnvStringList(name string, alias rune, params string, usage string, pointer *[]string, separator string, wrappers ...Wrapper) (*OptFlag, *EnvFlag) {
+	return CommandLine.AddOptEnv(name, alias, params, usage, StringList(pointer, separator), wrappers...)
+}
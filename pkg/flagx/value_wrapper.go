@@ -57,6 +57,10 @@ func (value *valueInlined) IsInlined() bool {
 	return value.inlined
 }
 
+func (value *valueInlined) Reset() {
+	Reset(value.value)
+}
+
 type wrapperDefaults struct {
 	defaults []string
 }
@@ -90,6 +94,10 @@ func (value *valueDefaults) IsInlined() bool {
 	return IsInlined(value.value)
 }
 
+func (value *valueDefaults) Reset() {
+	Reset(value.value)
+}
+
 type valueJoin struct {
 	values []Value
 }
@@ -119,6 +127,12 @@ func (value *valueJoin) Format() []string {
 	return result
 }
 
+func (value *valueJoin) Reset() {
+	for _, value := range value.values {
+		Reset(value)
+	}
+}
+
 type valueOptional struct {
 	value Value
 }
@@ -142,6 +156,10 @@ func (value *valueOptional) IsInlined() bool {
 	return IsInlined(value.value)
 }
 
+func (value *valueOptional) Reset() {
+	Reset(value.value)
+}
+
 type wrapperArgs struct {
 	args []string
 }
@@ -177,6 +195,10 @@ func (value *valueArgs) Parse(args ...string) (int, error) {
 	return 0, nil
 }
 
+func (value *valueArgs) Reset() {
+	Reset(value.value)
+}
+
 type wrapperEnum struct {
 	values []string
 }
@@ -223,3 +245,7 @@ func (value *valueEnum) Format() []string {
 func (value *valueEnum) IsInlined() bool {
 	return IsInlined(value.value)
 }
+
+func (value *valueEnum) Reset() {
+	Reset(value.value)
+}
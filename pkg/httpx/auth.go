@@ -0,0 +1,127 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mainden/stdhttp/pkg/errorsx"
+)
+
+var sensitiveHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Set-Cookie":          {},
+}
+
+func RedactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for key, values := range redacted {
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(key)]; !ok {
+			continue
+		}
+		for i := range values {
+			values[i] = errorsx.SecretString
+		}
+	}
+	return redacted
+}
+
+func ParseHeader(header string) (key string, value string, err error) {
+	key, value, ok := strings.Cut(header, ":")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid header '%v' (expected 'Name: value')", header)
+	}
+	return key, value, nil
+}
+
+func StripUserinfo(rawURL string) (string, *url.Userinfo, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, errors.New("failed to parse url")
+	}
+	user := u.User
+	u.User = nil
+	return u.String(), user, nil
+}
+
+func wrapSecrets(err error, secrets ...string) error {
+	for _, secret := range secrets {
+		if secret != "" {
+			err = errorsx.WrapSecret(secret, err)
+		}
+	}
+	return err
+}
+
+func WithSecretHeader(key string, value string) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header = req.Header.Clone()
+			req.Header.Set(key, value)
+			resp, err := client.Do(req)
+			return resp, wrapSecrets(err, value)
+		})
+	})
+}
+
+func WithAuthorization(scheme string, credentials string) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header = req.Header.Clone()
+			req.Header.Set("Authorization", scheme+" "+credentials)
+			resp, err := client.Do(req)
+			return resp, wrapSecrets(err, credentials)
+		})
+	})
+}
+
+func WithBearerToken(token string) Wrapper {
+	return WithAuthorization("Bearer", token)
+}
+
+func WithBearerTokenFile(path string) Wrapper {
+	return WithAuthorizationFile("Bearer", path)
+}
+
+func WithAuthorizationFile(scheme string, path string) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %v token file: %w", strings.ToLower(scheme), err)
+			}
+			token := strings.TrimSpace(string(data))
+			if token == "" {
+				return nil, fmt.Errorf("empty %v token file '%v'", strings.ToLower(scheme), path)
+			}
+			return WithAuthorization(scheme, token).Wrap(client).Do(req)
+		})
+	})
+}
+
+func WithBasicAuth(username string, password string) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header = req.Header.Clone()
+			req.SetBasicAuth(username, password)
+			resp, err := client.Do(req)
+			return resp, wrapSecrets(err, password, req.Header.Get("Authorization"))
+		})
+	})
+}
+
+func WithUserinfo(user *url.Userinfo) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		if user == nil {
+			return client
+		}
+		password, _ := user.Password()
+		return WithBasicAuth(user.Username(), password).Wrap(client)
+	})
+}
@@ -0,0 +1,162 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+var ErrCircuitOpen = errors.New("circuit open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	circuitNeutral
+)
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+	mutex     *sync.Mutex
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+		mutex:     &sync.Mutex{},
+	}
+}
+
+func (cb *circuitBreaker) circuit(host string) *circuit {
+	c, ok := cb.circuits[host]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[host] = c
+	}
+	return c
+}
+
+func (cb *circuitBreaker) transition(ctx context.Context, host string, c *circuit, state CircuitState) {
+	if c.state == state {
+		return
+	}
+	logx.WarnContext(ctx, "Circuit breaker state changed", "host", host, "from", c.state.String(), "to", state.String(), "failures", c.failures)
+	c.state = state
+	if state == CircuitOpen {
+		c.openedAt = time.Now()
+	}
+}
+
+func (cb *circuitBreaker) allow(ctx context.Context, host string) (probe bool, err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	c := cb.circuit(host)
+	switch c.state {
+	case CircuitOpen:
+		if remaining := cb.cooldown - time.Since(c.openedAt); remaining > 0 {
+			return false, fmt.Errorf("%w for host '%v' (retry in %v)", ErrCircuitOpen, host, remaining.Round(time.Millisecond))
+		}
+		cb.transition(ctx, host, c, CircuitHalfOpen)
+		c.probing = true
+		return true, nil
+	case CircuitHalfOpen:
+		if c.probing {
+			return false, fmt.Errorf("%w for host '%v' (probe in progress)", ErrCircuitOpen, host)
+		}
+		c.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (cb *circuitBreaker) report(ctx context.Context, host string, probe bool, outcome circuitOutcome) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	c := cb.circuit(host)
+	if probe {
+		c.probing = false
+	}
+	switch outcome {
+	case circuitNeutral:
+		// Nothing is known about the host, so only the probe slot is released
+		return
+	case circuitSuccess:
+		c.failures = 0
+		cb.transition(ctx, host, c, CircuitClosed)
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= cb.threshold {
+		cb.transition(ctx, host, c, CircuitOpen)
+	}
+}
+
+func circuitResult(resp *http.Response, err error) circuitOutcome {
+	if errors.Is(err, context.Canceled) {
+		return circuitNeutral
+	}
+	if err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return circuitFailure
+	}
+	return circuitSuccess
+}
+
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		if threshold <= 0 {
+			return client
+		}
+		cb := newCircuitBreaker(threshold, cooldown)
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := logx.WithName(req.Context(), "http_circuit_breaker")
+			host := req.URL.Host
+			probe, err := cb.allow(ctx, host)
+			if err != nil {
+				logx.DebugContext(ctx, "HTTP request rejected", "method", req.Method, "url", req.URL.Redacted(), "error", err.Error())
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			cb.report(ctx, host, probe, circuitResult(resp, err))
+			return resp, err
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var attempts, status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	client := WrapHttpClient(http.DefaultClient, WithCircuitBreaker(2, 50*time.Millisecond))
+	do := func() error {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %v", attempts.Load())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	status.Store(http.StatusNoContent)
+	if err := do(); err != nil {
+		t.Errorf("expected probe to pass, got %v", err)
+	}
+	if err := do(); err != nil {
+		t.Errorf("expected closed circuit, got %v", err)
+	}
+	if attempts.Load() != 4 {
+		t.Errorf("expected 4 attempts, got %v", attempts.Load())
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	ctx := context.Background()
+	cb := newCircuitBreaker(1, 0)
+	cb.report(ctx, "host", false, circuitFailure)
+	probe, err := cb.allow(ctx, "host")
+	if err != nil || !probe {
+		t.Fatalf("expected probe, got %v, %v", probe, err)
+	}
+	cb.report(ctx, "host", probe, circuitResult(nil, context.Canceled))
+	if state := cb.circuit("host").state; state != CircuitHalfOpen {
+		t.Errorf("expected state %v after cancelled probe, got %v", CircuitHalfOpen, state)
+	}
+	if probe, err := cb.allow(ctx, "host"); err != nil || !probe {
+		t.Errorf("expected another probe after cancelled probe, got %v, %v", probe, err)
+	}
+}
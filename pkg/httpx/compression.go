@@ -0,0 +1,137 @@
+package httpx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+const (
+	CompressionNone    = "none"
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+)
+
+func compress(encoding string, data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case CompressionGzip:
+		writer = gzip.NewWriter(&buffer)
+	case CompressionDeflate:
+		writer = zlib.NewWriter(&buffer)
+	default:
+		return nil, fmt.Errorf("unknown compression '%v'", encoding)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func WithCompression(encoding string, minSize int) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		if encoding == "" || encoding == CompressionNone {
+			return client
+		}
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+				return client.Do(req)
+			}
+			var data []byte
+			if err := AsData(req.Body, &data); err != nil {
+				return nil, fmt.Errorf("failed to read request body: %w", err)
+			}
+			if len(data) >= minSize {
+				compressed, err := compress(encoding, data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to compress request body: %w", err)
+				}
+				req = req.Clone(req.Context())
+				req.Header.Set("Content-Encoding", encoding)
+				data = compressed
+			}
+			req.ContentLength = int64(len(data))
+			req.Body = io.NopCloser(bytes.NewReader(data))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+			return client.Do(req)
+		})
+	})
+}
+
+type decompressionReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (reader *decompressionReader) Close() error {
+	var err error
+	for _, closer := range reader.closers {
+		if e := closer.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func decompress(encoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	switch encoding {
+	case "", "identity":
+		return body, nil
+	case CompressionGzip, "x-gzip":
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressionReader{Reader: reader, closers: []io.Closer{reader, body}}, nil
+	case CompressionDeflate:
+		reader, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressionReader{Reader: reader, closers: []io.Closer{reader, body}}, nil
+	default:
+		return nil, fmt.Errorf("%w '%v'", ErrUnsupportedContentEncoding, encoding)
+	}
+}
+
+func HandleDecompression(handler http.Handler, maxSize int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		if encoding != "" && encoding != "identity" {
+			body, err := decompress(encoding, r.Body)
+			if err != nil {
+				logx.DebugContext(r.Context(), "HTTP request body decompression failed", "method", r.Method, "url", r.URL.Redacted(), "encoding", encoding, "error", err.Error())
+				if errors.Is(err, ErrUnsupportedContentEncoding) {
+					http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+				} else {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				}
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Body = body
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+		}
+		if maxSize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
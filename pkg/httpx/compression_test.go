@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	payload := strings.Repeat("repetitive log line\n", 1000)
+	for _, encoding := range []string{CompressionGzip, CompressionDeflate} {
+		var received string
+		var contentLength int64
+		server := httptest.NewServer(HandleDecompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			received = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		}), 1<<20))
+		inspect := HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Content-Encoding") != encoding {
+				t.Errorf("expected encoding %v, got %q", encoding, req.Header.Get("Content-Encoding"))
+			}
+			contentLength = req.ContentLength
+			return http.DefaultClient.Do(req)
+		})
+		client := WrapHttpClient(inspect, WithCompression(encoding, 1024))
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		server.Close()
+		if received != payload {
+			t.Errorf("%v: unexpected body of size %v", encoding, len(received))
+		}
+		if contentLength >= int64(len(payload)) {
+			t.Errorf("%v: expected compressed body, got %v bytes", encoding, contentLength)
+		}
+	}
+}
+
+func TestDecompressionLimit(t *testing.T) {
+	compressed, err := compress(CompressionGzip, bytes.Repeat([]byte{0}, 1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := HandleDecompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if !errors.As(err, &maxBytesErr) {
+			t.Errorf("expected max bytes error, got %v", err)
+		}
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}), 1024)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %v", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("data"))
+	req.Header.Set("Content-Encoding", "br")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status 415, got %v", w.Code)
+	}
+}
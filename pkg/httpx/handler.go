@@ -25,14 +25,14 @@ func HandleEvent(handler http.Handler) http.Handler {
 
 func HandleLogger(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logx.DebugContext(r.Context(), "HTTP request received", "method", r.Method, "url", r.URL)
+		logx.DebugContext(r.Context(), "HTTP request received", "method", r.Method, "url", r.URL.Redacted())
 		bw := NewStatisticResponseWriter(w)
 		defer func() {
 			if bw.Err() != nil {
-				logx.DebugContext(r.Context(), "HTTP response failed", "method", r.Method, "url", r.URL, "status_code", bw.StatusCode(), "elapsed_time", bw.ElapsedTime(), "size", bw.Size(), "error", bw.Err())
+				logx.DebugContext(r.Context(), "HTTP response failed", "method", r.Method, "url", r.URL.Redacted(), "status_code", bw.StatusCode(), "elapsed_time", bw.ElapsedTime(), "size", bw.Size(), "error", bw.Err())
 				return
 			}
-			logx.DebugContext(r.Context(), "HTTP response sent", "method", r.Method, "url", r.URL, "status_code", bw.StatusCode(), "elapsed_time", bw.ElapsedTime(), "size", bw.Size())
+			logx.DebugContext(r.Context(), "HTTP response sent", "method", r.Method, "url", r.URL.Redacted(), "status_code", bw.StatusCode(), "elapsed_time", bw.ElapsedTime(), "size", bw.Size())
 		}()
 		handler.ServeHTTP(bw, r)
 	})
@@ -17,8 +17,29 @@ var (
 	ErrUnexpectedStatusCode = errors.New("unexpected status code")
 )
 
+type StatusCodeError struct {
+	StatusCode int
+}
+
+func (err *StatusCodeError) Error() string {
+	return fmt.Sprintf("%v (%d)", ErrUnexpectedStatusCode, err.StatusCode)
+}
+
+func (err *StatusCodeError) Unwrap() error {
+	return ErrUnexpectedStatusCode
+}
+
 func MakeErrorUnexpectedStatusCode(statusCode int) error {
-	return fmt.Errorf("%w (%d)", ErrUnexpectedStatusCode, statusCode)
+	return &StatusCodeError{StatusCode: statusCode}
+}
+
+func IsPermanentError(err error) bool {
+	var statusCodeError *StatusCodeError
+	if !errors.As(err, &statusCodeError) {
+		return false
+	}
+	code := statusCodeError.StatusCode
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
 }
 
 var Default HttpClient = WrapHttpClient(http.DefaultClient, WithLogger(), WithEvent())
@@ -62,6 +83,19 @@ func DoReader(ctx context.Context, method string, url string, body io.Reader) (*
 	return resp, nil
 }
 
+func DoData(ctx context.Context, method string, url string, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request: %w", err)
+	}
+	return resp, nil
+}
+
 func DoJson(ctx context.Context, method string, url string, body any) (*http.Response, error) {
 	bodyData, err := json.Marshal(body)
 	if err != nil {
@@ -160,6 +194,16 @@ func WithUserAgent(userAgent string) Wrapper {
 	})
 }
 
+func WithHeader(key string, value string) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header = req.Header.Clone()
+			req.Header.Set(key, value)
+			return client.Do(req)
+		})
+	})
+}
+
 func WithEvent() Wrapper {
 	return WrapperFunc(func(client HttpClient) HttpClient {
 		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
@@ -176,12 +220,12 @@ func WithLogger() Wrapper {
 	return WrapperFunc(func(client HttpClient) HttpClient {
 		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
 			req = req.WithContext(logx.WithName(req.Context(), "http_client"))
-			logx.DebugContext(req.Context(), "HTTP request", "method", req.Method, "url", req.URL.String())
+			logx.DebugContext(req.Context(), "HTTP request", "method", req.Method, "url", req.URL.Redacted())
 			resp, err := client.Do(req)
 			if err != nil {
-				logx.DebugContext(req.Context(), "HTTP request failed", "method", req.Method, "url", req.URL.String(), "error", err.Error())
+				logx.DebugContext(req.Context(), "HTTP request failed", "method", req.Method, "url", req.URL.Redacted(), "error", err.Error())
 			} else {
-				logx.DebugContext(req.Context(), "HTTP response", "method", req.Method, "url", req.URL.String(), "status_code", resp.StatusCode)
+				logx.DebugContext(req.Context(), "HTTP response", "method", req.Method, "url", req.URL.Redacted(), "status_code", resp.StatusCode)
 			}
 			return resp, err
 		})
@@ -0,0 +1,131 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+const retryBaseDelay = 200 * time.Millisecond
+
+type retryableContextKey struct{}
+
+func WithRetryable(ctx context.Context, retryable bool) context.Context {
+	return context.WithValue(ctx, retryableContextKey{}, retryable)
+}
+
+func IsRetryable(req *http.Request) bool {
+	if retryable, ok := req.Context().Value(retryableContextKey{}).(bool); ok {
+		return retryable
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetryStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode != http.StatusNotImplemented)
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func retryDelay(attempt int, maxDelay time.Duration, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(delay, maxDelay)
+		}
+	}
+	delay := maxDelay
+	if attempt < 32 {
+		delay = min(retryBaseDelay<<attempt, maxDelay)
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+}
+
+func WithRetry(attempts int, maxDelay time.Duration) Wrapper {
+	return WrapperFunc(func(client HttpClient) HttpClient {
+		if attempts <= 1 {
+			return client
+		}
+		if maxDelay <= 0 {
+			maxDelay = retryBaseDelay
+		}
+		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			if !IsRetryable(req) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+				return client.Do(req)
+			}
+			ctx := logx.WithName(req.Context(), "http_retry")
+			for attempt := 1; ; attempt++ {
+				attemptReq := req
+				if attempt > 1 {
+					var err error
+					if attemptReq, err = rewindRequest(req); err != nil {
+						return nil, err
+					}
+				}
+				resp, err := client.Do(attemptReq)
+				if attempt >= attempts || ctx.Err() != nil {
+					return resp, err
+				}
+				if err == nil && !shouldRetryStatusCode(resp.StatusCode) {
+					return resp, nil
+				}
+				delay := retryDelay(attempt-1, maxDelay, resp)
+				if err != nil {
+					logx.DebugContext(ctx, "HTTP request will be retried", "method", req.Method, "url", req.URL.Redacted(), "attempt", attempt, "delay", delay, "error", err.Error())
+				} else {
+					logx.DebugContext(ctx, "HTTP request will be retried", "method", req.Method, "url", req.URL.Redacted(), "attempt", attempt, "delay", delay, "status_code", resp.StatusCode)
+					discard(resp)
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		})
+	})
+}
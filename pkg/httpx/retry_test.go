@@ -0,0 +1,105 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := attempts.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("unexpected body on attempt %v: %q", attempt, body)
+		}
+		if attempt < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := WrapHttpClient(http.DefaultClient, WithRetry(3, time.Second))
+	ctx := WithRetryable(context.Background(), true)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status 204, got %v", resp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %v", attempts.Load())
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := WrapHttpClient(http.DefaultClient, WithRetry(3, time.Millisecond))
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %v", attempts.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if delay, ok := parseRetryAfter("5", now); !ok || delay != 5*time.Second {
+		t.Errorf("expected 5s, got %v", delay)
+	}
+	if delay, ok := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); !ok || delay != time.Minute {
+		t.Errorf("expected 1m, got %v", delay)
+	}
+	if _, ok := parseRetryAfter("invalid", now); ok {
+		t.Error("expected invalid value")
+	}
+}
+
+func TestIsPermanentError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{MakeErrorUnexpectedStatusCode(http.StatusBadRequest), true},
+		{MakeErrorUnexpectedStatusCode(http.StatusRequestEntityTooLarge), true},
+		{MakeErrorUnexpectedStatusCode(http.StatusRequestTimeout), false},
+		{MakeErrorUnexpectedStatusCode(http.StatusTooManyRequests), false},
+		{MakeErrorUnexpectedStatusCode(http.StatusServiceUnavailable), false},
+		{errors.New("connection refused"), false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := IsPermanentError(test.err); got != test.want {
+			t.Errorf("error %v: expected %v, got %v", test.err, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package httpx
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrCertificatePinMismatch = errors.New("certificate pin mismatch")
+
+type TLSOptions struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	MinVersion         string
+	InsecureSkipVerify bool
+	PinSHA256          []string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func ParseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	if v, ok := tlsVersions[version]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown tls version '%v'", version)
+}
+
+func CertificatePin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func verifyCertificatePins(pins []string) func(state tls.ConnectionState) error {
+	allowed := make(map[string]struct{}, len(pins))
+	for _, pin := range pins {
+		allowed[pin] = struct{}{}
+	}
+	return func(state tls.ConnectionState) error {
+		for _, cert := range state.PeerCertificates {
+			if _, ok := allowed[CertificatePin(cert)]; ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w for '%v'", ErrCertificatePinMismatch, state.ServerName)
+	}
+}
+
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	minVersion, err := ParseTLSVersion(options.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		ServerName:         options.ServerName,
+		MinVersion:         minVersion,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+	if options.CAFile != "" {
+		data, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in ca file '%v'", options.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if options.CertFile != "" || options.KeyFile != "" {
+		if options.CertFile == "" || options.KeyFile == "" {
+			return nil, errors.New("both cert file and key file are required")
+		}
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if len(options.PinSHA256) > 0 {
+		config.VerifyConnection = verifyCertificatePins(options.PinSHA256)
+	}
+	return config, nil
+}
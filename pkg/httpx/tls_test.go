@@ -0,0 +1,58 @@
+package httpx
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	do := func(options TLSOptions) error {
+		config, err := NewTLSConfig(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := NewHttpClient(TransportOptions{TLSConfig: config})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	if err := do(TLSOptions{}); err == nil {
+		t.Error("expected unknown authority error")
+	}
+	if err := do(TLSOptions{CAFile: caFile, ServerName: "example.com"}); err != nil {
+		t.Errorf("expected trusted server, got %v", err)
+	}
+	if err := do(TLSOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("expected skipped verification, got %v", err)
+	}
+	if err := do(TLSOptions{CAFile: caFile, ServerName: "example.com", PinSHA256: []string{CertificatePin(server.Certificate())}}); err != nil {
+		t.Errorf("expected pinned server, got %v", err)
+	}
+	if err := do(TLSOptions{InsecureSkipVerify: true, PinSHA256: []string{"invalid"}}); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("expected pin mismatch, got %v", err)
+	}
+	if _, err := NewTLSConfig(TLSOptions{MinVersion: "2.0"}); err == nil {
+		t.Error("expected unknown version error")
+	}
+}
@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type TransportOptions struct {
+	TLSConfig             *tls.Config
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	Proxy                 string
+	UnixSocket            string
+}
+
+type unixTransport struct {
+	transport *http.Transport
+}
+
+func (t *unixTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	return t.transport.RoundTrip(req)
+}
+
+func NewHttpClient(options TransportOptions) (*http.Client, error) {
+	keepAlive := options.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = -1
+	}
+	dialer := &net.Dialer{
+		Timeout:   options.DialTimeout,
+		KeepAlive: keepAlive,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.TLSClientConfig = options.TLSConfig
+	transport.TLSHandshakeTimeout = options.TLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = options.ResponseHeaderTimeout
+	transport.IdleConnTimeout = options.IdleConnTimeout
+	transport.MaxIdleConns = options.MaxIdleConns
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	if options.Proxy != "" {
+		proxy, err := url.Parse(options.Proxy)
+		if err != nil {
+			return nil, errors.New("failed to parse proxy url")
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if options.UnixSocket != "" {
+		socket := options.UnixSocket
+		unix := transport.Clone()
+		unix.Proxy = nil
+		unix.DialContext = func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, "unix", socket)
+			if err != nil {
+				return nil, fmt.Errorf("failed to dial unix socket: %w", err)
+			}
+			return conn, nil
+		}
+		transport.RegisterProtocol("http+unix", &unixTransport{transport: unix})
+	}
+	return &http.Client{Transport: transport, Timeout: options.Timeout}, nil
+}
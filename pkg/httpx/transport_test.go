@@ -0,0 +1,39 @@
+package httpx
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "http.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skip(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logs" {
+			t.Errorf("unexpected path %v", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client, err := NewHttpClient(TransportOptions{UnixSocket: socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get("http+unix://localhost/logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status 204, got %v", resp.StatusCode)
+	}
+}
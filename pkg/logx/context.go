@@ -7,7 +7,6 @@ import (
 	"maps"
 	"math/rand"
 	"strings"
-	"time"
 )
 
 func init() {
@@ -18,8 +17,6 @@ func init() {
 	RegisterContextAnyArg("context_values", GetValuesAny)
 }
 
-var eventRand = rand.New(rand.NewSource(time.Now().UnixNano()))
-
 type nameContextKey struct{}
 
 type eventBaseContextKey struct{}
@@ -49,7 +46,7 @@ func GetName(ctx context.Context) string {
 }
 
 func MakeEventId(event string) string {
-	return event + ":" + strings.ToLower(base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(binary.LittleEndian.AppendUint64(make([]byte, 0, 8), eventRand.Uint64())))
+	return event + ":" + strings.ToLower(base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(binary.LittleEndian.AppendUint64(make([]byte, 0, 8), rand.Uint64())))
 }
 
 func SetEventId(ctx context.Context, eventId string) context.Context {
@@ -0,0 +1,118 @@
+package envx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var ErrInvalidDotenv = errors.New("invalid dotenv")
+
+func ReadDotenv(path string, lookup func(key string) (string, bool)) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+	env, err := ParseDotenv(file, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse env file %s: %w", path, err)
+	}
+	return env, nil
+}
+
+func ParseDotenv(r io.Reader, lookup func(key string) (string, bool)) ([]string, error) {
+	var env []string
+	values := make(map[string]string)
+	expand := func(key string) string {
+		if value, ok := values[key]; ok {
+			return value
+		}
+		if lookup != nil {
+			value, _ := lookup(key)
+			return value
+		}
+		return ""
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isKey(key) {
+			return nil, fmt.Errorf("%w (line %d)", ErrInvalidDotenv, line)
+		}
+		value, err := parseValue(strings.TrimSpace(value), expand)
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d)", err, line)
+		}
+		values[key] = value
+		env = append(env, key+"="+value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env: %w", err)
+	}
+	return env, nil
+}
+
+func parseValue(value string, expand func(key string) string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated quote", ErrInvalidDotenv)
+		}
+		return value[1 : end+1], nil
+	case strings.HasPrefix(value, "\""):
+		var builder strings.Builder
+		for i := 1; i < len(value); i++ {
+			switch c := value[i]; c {
+			case '"':
+				return os.Expand(builder.String(), expand), nil
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					builder.WriteByte(unescape(value[i]))
+				}
+			default:
+				builder.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("%w: unterminated quote", ErrInvalidDotenv)
+	}
+	if index := strings.Index(value, " #"); index >= 0 {
+		value = strings.TrimSpace(value[:index])
+	}
+	return os.Expand(value, expand), nil
+}
+
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	}
+	return c
+}
+
+func isKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
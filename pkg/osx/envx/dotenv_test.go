@@ -0,0 +1,53 @@
+package envx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `# comment
+A=1
+export B = two words # trailing comment
+C='single $A # kept'
+D="double $A\n${B}"
+E=${HOME}/bin
+
+F=
+`
+	lookup := func(key string) (string, bool) {
+		if key == "HOME" {
+			return "/home/user", true
+		}
+		return "", false
+	}
+	env, err := ParseDotenv(strings.NewReader(input), lookup)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"A=1", "B=two words", "C=single $A # kept", "D=double 1\ntwo words", "E=/home/user/bin", "F="}
+	if len(env) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, env)
+	}
+	for i := range expected {
+		if env[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], env[i])
+		}
+	}
+}
+
+func TestParseDotenvInvalid(t *testing.T) {
+	for _, input := range []string{"NOVALUE", "1A=1", "A='unterminated", `A="unterminated`} {
+		if _, err := ParseDotenv(strings.NewReader(input), nil); !errors.Is(err, ErrInvalidDotenv) {
+			t.Errorf("%q: expected %v, got %v", input, ErrInvalidDotenv, err)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	env := Allow([]string{"PATH=/bin", "HOME=/root", "LC_ALL=C", "SECRET=x"}, []string{"PATH", "LC_*"})
+	if strings.Join(env, ",") != "PATH=/bin,LC_ALL=C" {
+		t.Errorf("unexpected env %q", env)
+	}
+}
@@ -0,0 +1,31 @@
+package envx
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidEnv = errors.New("invalid environment variable")
+
+func Allow(env []string, patterns []string) []string {
+	var allowed []string
+	for _, entry := range env {
+		key, _, _ := strings.Cut(entry, "=")
+		for _, pattern := range patterns {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(key, prefix) || key == pattern {
+				allowed = append(allowed, entry)
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+func Lookup(env []string, key string) (string, bool) {
+	for i := len(env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(env[i], "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,336 @@
+package execx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	cgroupCPUPeriod     = 100000
+	cgroupRemoveRetries = 10
+	cgroupRemoveDelay   = 10 * time.Millisecond
+)
+
+var ErrCgroupNotAvailable = errors.New("cgroup v2 not available")
+
+type CgroupOptions struct {
+	Parent    string
+	MemoryMax int64
+	CPUMax    float64
+	PidsMax   int64
+}
+
+type CgroupStats struct {
+	MemoryPeak int64
+	OOMKills   int64
+}
+
+type Cgroups struct {
+	path    string
+	options CgroupOptions
+	mount   string
+	origin  string
+	leaf    string
+	enabled []string
+	created string
+}
+
+func NewCgroups(options CgroupOptions) (*Cgroups, error) {
+	mount, err := cgroupMount()
+	if err != nil {
+		return nil, err
+	}
+	path := options.Parent
+	if path == "" {
+		if path, err = cgroupSelf(); err != nil {
+			return nil, err
+		}
+	}
+	if !filepath.IsAbs(path) || !strings.HasPrefix(path, mount) {
+		path = filepath.Join(mount, path)
+	}
+	created := missingDir(path)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	cgroups := &Cgroups{path: path, options: options, mount: mount, created: created}
+	if err := cgroups.enableControllers(); err != nil {
+		return nil, errors.Join(err, cgroups.Close())
+	}
+	return cgroups, nil
+}
+
+func missingDir(path string) string {
+	var missing string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			return missing
+		}
+		missing = dir
+	}
+}
+
+func (cgroups *Cgroups) controllers() []string {
+	var controllers []string
+	if cgroups.options.MemoryMax > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if cgroups.options.CPUMax > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if cgroups.options.PidsMax > 0 {
+		controllers = append(controllers, "pids")
+	}
+	return controllers
+}
+
+func (cgroups *Cgroups) enableControllers() error {
+	data, err := os.ReadFile(filepath.Join(cgroups.path, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("failed to read cgroup controllers: %w", err)
+	}
+	available := strings.Fields(string(data))
+	data, err = os.ReadFile(filepath.Join(cgroups.path, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("failed to read cgroup subtree control: %w", err)
+	}
+	enabled := strings.Fields(string(data))
+	var values []string
+	for _, controller := range cgroups.controllers() {
+		if !slices.Contains(available, controller) {
+			return fmt.Errorf("%w (controller %s)", ErrCgroupNotAvailable, controller)
+		}
+		if !slices.Contains(enabled, controller) {
+			values = append(values, "+"+controller)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	err = cgroups.writeSubtreeControl(values)
+	if errors.Is(err, syscall.EBUSY) {
+		// Processes cannot live in a cgroup with enabled controllers, so move this process to a leaf until Close
+		self, err := cgroupSelf()
+		if err != nil {
+			return err
+		}
+		cgroups.origin = filepath.Join(cgroups.mount, self)
+		leaf := filepath.Join(cgroups.path, "stdhttp-"+strconv.Itoa(os.Getpid()))
+		if err := os.Mkdir(leaf, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to create cgroup: %w", err)
+		}
+		cgroups.leaf = leaf
+		if err := moveToCgroup(leaf); err != nil {
+			return err
+		}
+		err = cgroups.writeSubtreeControl(values)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enable cgroup controllers: %w", err)
+	}
+	for _, value := range values {
+		cgroups.enabled = append(cgroups.enabled, strings.TrimPrefix(value, "+"))
+	}
+	return nil
+}
+
+func (cgroups *Cgroups) writeSubtreeControl(values []string) error {
+	return os.WriteFile(filepath.Join(cgroups.path, "cgroup.subtree_control"), []byte(strings.Join(values, " ")), 0o644)
+}
+
+func (cgroups *Cgroups) Close() error {
+	if cgroups == nil {
+		return nil
+	}
+	errs := cgroups.restore()
+	if cgroups.leaf != "" {
+		return errs
+	}
+	// Parents created by MkdirAll are removed up to the first one that did not exist
+	for dir := cgroups.path; cgroups.created != ""; dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = errors.Join(errs, fmt.Errorf("failed to remove cgroup: %w", err))
+			break
+		}
+		if dir == cgroups.created {
+			break
+		}
+	}
+	cgroups.created = ""
+	return errs
+}
+
+func (cgroups *Cgroups) restore() error {
+	if cgroups.leaf == "" {
+		return nil
+	}
+	// Restore the placement of this process, which requires the controllers enabled by it to be disabled again
+	var errs error
+	var values []string
+	for _, controller := range cgroups.enabled {
+		values = append(values, "-"+controller)
+	}
+	if len(values) > 0 {
+		if err := cgroups.writeSubtreeControl(values); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to disable cgroup controllers: %w", err))
+		}
+	}
+	if err := moveToCgroup(cgroups.origin); err != nil {
+		return errors.Join(errs, err)
+	}
+	if err := os.Remove(cgroups.leaf); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = errors.Join(errs, fmt.Errorf("failed to remove cgroup: %w", err))
+	}
+	cgroups.leaf = ""
+	return errs
+}
+
+func moveToCgroup(path string) error {
+	if err := os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+		return fmt.Errorf("failed to move process to cgroup: %w", err)
+	}
+	return nil
+}
+
+func (cgroups *Cgroups) New(name string) (*Cgroup, error) {
+	path := filepath.Join(cgroups.path, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	cgroup := &Cgroup{path: path}
+	if err := cgroup.limit(cgroups.options); err != nil {
+		return nil, errors.Join(err, cgroup.Close())
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to open cgroup: %w", err), cgroup.Close())
+	}
+	cgroup.dir = dir
+	return cgroup, nil
+}
+
+type Cgroup struct {
+	path string
+	dir  *os.File
+}
+
+func (cgroup *Cgroup) limit(options CgroupOptions) error {
+	if options.MemoryMax > 0 {
+		if err := cgroup.write("memory.max", strconv.FormatInt(options.MemoryMax, 10)); err != nil {
+			return err
+		}
+	}
+	if options.CPUMax > 0 {
+		if err := cgroup.write("cpu.max", fmt.Sprintf("%d %d", int64(options.CPUMax*cgroupCPUPeriod), cgroupCPUPeriod)); err != nil {
+			return err
+		}
+	}
+	if options.PidsMax > 0 {
+		if err := cgroup.write("pids.max", strconv.FormatInt(options.PidsMax, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cgroup *Cgroup) write(name string, value string) error {
+	if err := os.WriteFile(filepath.Join(cgroup.path, name), []byte(value), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (cgroup *Cgroup) Prepare(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(cgroup.dir.Fd())
+}
+
+func (cgroup *Cgroup) Stats() (CgroupStats, error) {
+	var stats CgroupStats
+	data, err := os.ReadFile(filepath.Join(cgroup.path, "memory.peak"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return stats, fmt.Errorf("failed to read memory peak: %w", err)
+	}
+	if err == nil {
+		stats.MemoryPeak, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	}
+	file, err := os.Open(filepath.Join(cgroup.path, "memory.events"))
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, fmt.Errorf("failed to open memory events: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "oom_kill "); ok {
+			stats.OOMKills, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return stats, nil
+}
+
+func (cgroup *Cgroup) Close() error {
+	var errs error
+	if cgroup.dir != nil {
+		errs = errors.Join(errs, cgroup.dir.Close())
+	}
+	if _, err := os.Stat(filepath.Join(cgroup.path, "cgroup.kill")); err == nil {
+		errs = errors.Join(errs, cgroup.write("cgroup.kill", "1"))
+	}
+	var err error
+	for i := 0; i < cgroupRemoveRetries; i++ {
+		if err = os.Remove(cgroup.path); !errors.Is(err, syscall.EBUSY) {
+			break
+		}
+		time.Sleep(cgroupRemoveDelay)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = errors.Join(errs, fmt.Errorf("failed to remove cgroup: %w", err))
+	}
+	return errs
+}
+
+func cgroupMount() (string, error) {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", fmt.Errorf("failed to open mountinfo: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) && fields[i+1] == "cgroup2" && len(fields) > 4 {
+				return fields[4], nil
+			}
+		}
+	}
+	return "", ErrCgroupNotAvailable
+}
+
+func cgroupSelf() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", fmt.Errorf("failed to read cgroup: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", ErrCgroupNotAvailable
+}
@@ -0,0 +1,29 @@
+package execx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMissingDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: dir, want: ""},
+		{path: filepath.Join(dir, "a"), want: ""},
+		{path: filepath.Join(dir, "a", "b"), want: filepath.Join(dir, "a", "b")},
+		{path: filepath.Join(dir, "a", "b", "c"), want: filepath.Join(dir, "a", "b")},
+		{path: filepath.Join(dir, "x", "y"), want: filepath.Join(dir, "x")},
+	}
+	for _, test := range tests {
+		if got := missingDir(test.path); got != test.want {
+			t.Errorf("%v: expected %q, got %q", test.path, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !linux
+
+package execx
+
+import (
+	"errors"
+	"os/exec"
+)
+
+var ErrCgroupNotAvailable = errors.New("cgroup v2 not available")
+
+type CgroupOptions struct {
+	Parent    string
+	MemoryMax int64
+	CPUMax    float64
+	PidsMax   int64
+}
+
+type CgroupStats struct {
+	MemoryPeak int64
+	OOMKills   int64
+}
+
+type Cgroups struct{}
+
+func NewCgroups(options CgroupOptions) (*Cgroups, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func (cgroups *Cgroups) Close() error {
+	return nil
+}
+
+func (cgroups *Cgroups) New(name string) (*Cgroup, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+type Cgroup struct{}
+
+func (cgroup *Cgroup) Prepare(cmd *exec.Cmd) {}
+
+func (cgroup *Cgroup) Stats() (CgroupStats, error) {
+	return CgroupStats{}, nil
+}
+
+func (cgroup *Cgroup) Close() error {
+	return nil
+}
@@ -0,0 +1,5 @@
+package execx
+
+import "errors"
+
+var ErrCredentialNotSupported = errors.New("user switching not supported")
@@ -0,0 +1,86 @@
+//go:build !windows
+
+package execx
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+type Credential struct {
+	User       *user.User
+	credential *syscall.Credential
+}
+
+func LookupCredential(userName string, groupName string) (*Credential, error) {
+	if userName == "" && groupName == "" {
+		return nil, nil
+	}
+	credential := &syscall.Credential{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+	var u *user.User
+	if userName != "" {
+		var err error
+		if u, err = LookupUser(userName); err != nil {
+			return nil, err
+		}
+		uid, _ := strconv.ParseUint(u.Uid, 10, 32)
+		gid, _ := strconv.ParseUint(u.Gid, 10, 32)
+		credential.Uid, credential.Gid = uint32(uid), uint32(gid)
+		if groups, err := u.GroupIds(); err == nil {
+			for _, group := range groups {
+				if gid, err := strconv.ParseUint(group, 10, 32); err == nil {
+					credential.Groups = append(credential.Groups, uint32(gid))
+				}
+			}
+		}
+	}
+	if groupName != "" {
+		g, err := lookupGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		gid, _ := strconv.ParseUint(g.Gid, 10, 32)
+		credential.Gid = uint32(gid)
+	}
+	credential.NoSetGroups = userName == ""
+	return &Credential{User: u, credential: credential}, nil
+}
+
+func CmdCredential(cmd *exec.Cmd, credential *Credential) {
+	if credential == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = credential.credential
+}
+
+func LookupUser(name string) (*user.User, error) {
+	if _, err := strconv.ParseUint(name, 10, 32); err == nil {
+		if u, err := user.LookupId(name); err == nil {
+			return u, nil
+		}
+		return &user.User{Uid: name, Gid: strconv.Itoa(os.Getgid()), Username: name}, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup user: %w", err)
+	}
+	return u, nil
+}
+
+func lookupGroup(name string) (*user.Group, error) {
+	if _, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return &user.Group{Gid: name, Name: name}, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup group: %w", err)
+	}
+	return g, nil
+}
@@ -0,0 +1,23 @@
+package execx
+
+import (
+	"os/exec"
+	"os/user"
+)
+
+type Credential struct {
+	User *user.User
+}
+
+func LookupCredential(userName string, groupName string) (*Credential, error) {
+	if userName == "" && groupName == "" {
+		return nil, nil
+	}
+	return nil, ErrCredentialNotSupported
+}
+
+func CmdCredential(cmd *exec.Cmd, credential *Credential) {}
+
+func LookupUser(name string) (*user.User, error) {
+	return nil, ErrCredentialNotSupported
+}
@@ -0,0 +1,40 @@
+package execx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type ExecStatus struct {
+	r *os.File
+	w *os.File
+}
+
+func newExecStatus() (*ExecStatus, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec status pipe: %w", err)
+	}
+	return &ExecStatus{r: r, w: w}, nil
+}
+
+func (status *ExecStatus) Wait() error {
+	// The pipe is closed without data once the command replaces the helper process
+	status.w.Close()
+	data, err := io.ReadAll(status.r)
+	status.r.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read exec status: %w", err)
+	}
+	if len(data) > 0 {
+		return errors.New(strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+func (status *ExecStatus) Close() error {
+	return errors.Join(status.w.Close(), status.r.Close())
+}
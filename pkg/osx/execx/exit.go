@@ -0,0 +1,18 @@
+package execx
+
+import "os"
+
+const (
+	ExitCodeSignalBase  = 128
+	ExitCodeStartFailed = 127
+)
+
+func ExitCode(state *os.ProcessState) int {
+	if signal, ok := ExitSignal(state); ok {
+		return ExitCodeSignalBase + int(signal)
+	}
+	if state == nil {
+		return ExitCodeStartFailed
+	}
+	return state.ExitCode()
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package execx
+
+import (
+	"os"
+	"syscall"
+)
+
+func ExitSignal(state *os.ProcessState) (syscall.Signal, bool) {
+	if state == nil {
+		return 0, false
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return 0, false
+	}
+	return status.Signal(), true
+}
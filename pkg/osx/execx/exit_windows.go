@@ -0,0 +1,10 @@
+package execx
+
+import (
+	"os"
+	"syscall"
+)
+
+func ExitSignal(state *os.ProcessState) (syscall.Signal, bool) {
+	return 0, false
+}
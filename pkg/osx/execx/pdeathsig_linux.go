@@ -0,0 +1,13 @@
+package execx
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func CmdParentDeathSignal(cmd *exec.Cmd, signal syscall.Signal) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Pdeathsig = signal
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package execx
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func CmdParentDeathSignal(cmd *exec.Cmd, signal syscall.Signal) {
+	// Supported on Linux only
+}
@@ -0,0 +1,118 @@
+package execx
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func runSuspendHelper(t *testing.T) {
+	group := NewProcessGroup()
+	cmd := exec.Command("sh", "-c", `echo "started $$ $PPID."; read line; echo "got $line"`)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	group.Prepare(cmd)
+	if group.tty == nil {
+		t.Fatal("expected the terminal to be handed to the command")
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := group.Add(cmd); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+	if err := group.Release(cmd); err != nil {
+		t.Error(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessGroupSuspend(t *testing.T) {
+	if os.Getenv("STDHTTP_TEST_SUSPEND_HELPER") == "1" {
+		runSuspendHelper(t)
+		return
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not available")
+	}
+	pty, err := OpenPty()
+	if err != nil {
+		t.Skip("pty is not available")
+	}
+	defer pty.Close()
+	// The shell runs the helper as a foreground job, like a user running stdhttp
+	cmd := exec.Command(bash, "-m", "-c", `"$0" -test.run='^TestProcessGroupSuspend$'; echo "suspended $?"; fg; echo "resumed $?"`, os.Args[0])
+	cmd.Env = append(os.Environ(), "STDHTTP_TEST_SUSPEND_HELPER=1")
+	pty.Prepare(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+	pty.CloseTty()
+
+	var mutex sync.Mutex
+	var output bytes.Buffer
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			n, err := pty.Read(buffer)
+			mutex.Lock()
+			output.Write(buffer[:n])
+			mutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	waitFor := func(text string) {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			mutex.Lock()
+			found := strings.Contains(output.String(), text)
+			mutex.Unlock()
+			if found {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		t.Fatalf("expected output to contain %q, got %q", text, output.String())
+	}
+
+	waitFor("started")
+	waitFor(".")
+	mutex.Lock()
+	var pid, helperPid int
+	_, _ = fmt.Sscanf(output.String()[strings.Index(output.String(), "started"):], "started %d %d.", &pid, &helperPid)
+	mutex.Unlock()
+	defer func() {
+		// A failed test must not leave the stopped processes behind
+		for _, pgid := range []int{pid, helperPid} {
+			if pgid > 0 {
+				_ = syscall.Kill(-pgid, syscall.SIGKILL)
+			}
+		}
+	}()
+	if _, err := pty.Write([]byte{0x1a}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("suspended 148")
+	if _, err := pty.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("got hello")
+	waitFor("resumed 0")
+	if err := cmd.Wait(); err != nil {
+		t.Error(err)
+	}
+}
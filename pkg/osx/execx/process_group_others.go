@@ -2,20 +2,156 @@
 
 package execx
 
-import "os/exec"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"syscall"
+)
 
-type ProcessGroup struct{}
+type ProcessGroup struct {
+	pgids      []int
+	tty        *os.File
+	control    chan struct{}
+	controlled chan struct{}
+}
 
 func NewProcessGroup() *ProcessGroup {
 	return &ProcessGroup{}
 }
 
+func (pg *ProcessGroup) Prepare(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// Children in their own session already lead their own process group
+	if cmd.SysProcAttr.Setsid {
+		return
+	}
+	cmd.SysProcAttr.Setpgid = true
+	pg.tty = nil
+	if file, ok := cmd.Stdin.(*os.File); ok && terminalJobControl && IsTerminal(file.Fd()) {
+		// The child's group takes over the terminal only if stdhttp owns it, so that keyboard signals and input reach the child
+		if pgid, err := foregroundProcessGroup(file.Fd()); err == nil && pgid == syscall.Getpgrp() {
+			cmd.SysProcAttr.Foreground = true
+			cmd.SysProcAttr.Ctty = int(file.Fd())
+			pg.tty = file
+		}
+	}
+}
+
 func (pg *ProcessGroup) Add(cmd *exec.Cmd) error {
-	// Handled by SIGTERM
+	if isProcessGroupLeader(cmd) {
+		pg.pgids = append(pg.pgids, cmd.Process.Pid)
+		if pg.tty != nil {
+			pg.control, pg.controlled = make(chan struct{}), make(chan struct{})
+			go controlJob(pg.tty, cmd.Process.Pid, pg.control, pg.controlled)
+		}
+	}
 	return nil
 }
 
+func controlJob(tty *os.File, pid int, done <-chan struct{}, closed chan<- struct{}) {
+	defer close(closed)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGCHLD)
+	defer signal.Stop(signals)
+	for {
+		if stopped, err := processStopped(pid); err == nil && stopped {
+			suspendJob(tty, pid, done)
+		}
+		select {
+		case <-done:
+			return
+		case <-signals:
+		}
+	}
+}
+
+func suspendJob(tty *os.File, pgid int, done <-chan struct{}) {
+	// A stopped command gives the terminal back and stops stdhttp, so that the shell regains control, as with a shell job
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+	_ = setForegroundProcessGroup(tty.Fd(), syscall.Getpgrp())
+	// Stop signals are discarded in orphaned process groups, which have no shell to continue them
+	if !signal.Ignored(syscall.SIGTSTP) && !orphanedProcessGroup() {
+		continued := make(chan os.Signal, 1)
+		signal.Notify(continued, syscall.SIGCONT)
+		// The stop takes effect asynchronously, the terminal is only taken again after the continue
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+		select {
+		case <-continued:
+		case <-done:
+		}
+		signal.Stop(continued)
+	}
+	// Continued in the foreground the command gets the terminal again, continued in the background it runs without it
+	if foreground, err := foregroundProcessGroup(tty.Fd()); err == nil && foreground == syscall.Getpgrp() {
+		_ = setForegroundProcessGroup(tty.Fd(), pgid)
+	}
+	_ = syscall.Kill(-pgid, syscall.SIGCONT)
+}
+
+func (pg *ProcessGroup) Release(cmd *exec.Cmd) error {
+	var errs error
+	if pg.control != nil {
+		close(pg.control)
+		<-pg.controlled
+		pg.control, pg.controlled = nil, nil
+	}
+	if pg.tty != nil {
+		// A background process group is stopped by SIGTTOU when it takes the terminal back
+		signal.Ignore(syscall.SIGTTOU)
+		if err := setForegroundProcessGroup(pg.tty.Fd(), syscall.Getpgrp()); err != nil {
+			errs = fmt.Errorf("failed to restore foreground process group: %w", err)
+		}
+		signal.Reset(syscall.SIGTTOU)
+		pg.tty = nil
+	}
+	if cmd.Process == nil {
+		return errs
+	}
+	if i := slices.Index(pg.pgids, cmd.Process.Pid); i >= 0 {
+		pg.pgids = slices.Delete(pg.pgids, i, i+1)
+		errs = errors.Join(errs, killProcessGroup(cmd.Process.Pid))
+	}
+	return errs
+}
+
 func (pg *ProcessGroup) Close() error {
-	// Handled by SIGTERM
-	return nil
+	var errs error
+	for _, pgid := range pg.pgids {
+		errs = errors.Join(errs, killProcessGroup(pgid))
+	}
+	pg.pgids = nil
+	return errs
+}
+
+func killProcessGroup(pgid int) error {
+	if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return fmt.Errorf("failed to kill process group %d: %w", pgid, err)
+	}
+	// Killed members that are children of stdhttp are reaped, other members are reaped by their own parent or by init
+	for {
+		if _, err := syscall.Wait4(-pgid, nil, 0, nil); err != nil && !errors.Is(err, syscall.EINTR) {
+			return nil
+		}
+	}
+}
+
+func Signal(cmd *exec.Cmd, signal os.Signal) error {
+	if sig, ok := signal.(syscall.Signal); ok && isProcessGroupLeader(cmd) {
+		return syscall.Kill(-cmd.Process.Pid, sig)
+	}
+	return cmd.Process.Signal(signal)
+}
+
+func isProcessGroupLeader(cmd *exec.Cmd) bool {
+	return cmd.Process != nil && cmd.SysProcAttr != nil && (cmd.SysProcAttr.Setsid || cmd.SysProcAttr.Setpgid && cmd.SysProcAttr.Pgid == 0)
 }
@@ -2,6 +2,7 @@ package execx
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
 	"unsafe"
@@ -81,6 +82,10 @@ func NewProcessGroup() *ProcessGroup {
 	return &ProcessGroup{}
 }
 
+func (pg *ProcessGroup) Prepare(cmd *exec.Cmd) {
+	// Handled by job object
+}
+
 func (pg *ProcessGroup) Add(cmd *exec.Cmd) error {
 	if pg.job == 0 {
 		job, err := CreateJobObject()
@@ -102,9 +107,18 @@ func (pg *ProcessGroup) Add(cmd *exec.Cmd) error {
 	return nil
 }
 
+func (pg *ProcessGroup) Release(cmd *exec.Cmd) error {
+	// Processes stay in the job object until it is closed
+	return nil
+}
+
 func (pg *ProcessGroup) Close() error {
 	if pg.job != 0 {
 		return syscall.CloseHandle(pg.job)
 	}
 	return nil
 }
+
+func Signal(cmd *exec.Cmd, signal os.Signal) error {
+	return cmd.Process.Signal(signal)
+}
@@ -0,0 +1,123 @@
+package execx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+var ResizeSignals = []os.Signal{syscall.SIGWINCH}
+
+type Pty struct {
+	master *os.File
+	tty    *os.File
+}
+
+func OpenPty() (*Pty, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pty: %w", err)
+	}
+	var unlock int32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to unlock pty: %w", err), master.Close())
+	}
+	var number uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number))); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to get pty number: %w", err), master.Close())
+	}
+	tty, err := os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(number), 10), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to open tty: %w", err), master.Close())
+	}
+	// Keep newlines as they are, the real terminal translates them if needed
+	var termios syscall.Termios
+	if err := ioctl(tty.Fd(), syscall.TCGETS, uintptr(unsafe.Pointer(&termios))); err == nil {
+		termios.Oflag &^= syscall.OPOST
+		_ = ioctl(tty.Fd(), syscall.TCSETS, uintptr(unsafe.Pointer(&termios)))
+	}
+	return &Pty{master: master, tty: tty}, nil
+}
+
+func (pty *Pty) Prepare(cmd *exec.Cmd) {
+	cmd.Stdin = pty.tty
+	cmd.Stdout = pty.tty
+	cmd.Stderr = pty.tty
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+}
+
+func (pty *Pty) Read(p []byte) (int, error) {
+	n, err := pty.master.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		// The master reports EIO once every tty descriptor is closed
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (pty *Pty) Write(p []byte) (int, error) {
+	return pty.master.Write(p)
+}
+
+func (pty *Pty) Resize(fd uintptr) error {
+	var size [4]uint16
+	if err := ioctl(fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size))); err != nil {
+		return fmt.Errorf("failed to get window size: %w", err)
+	}
+	if err := ioctl(pty.master.Fd(), syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&size))); err != nil {
+		return fmt.Errorf("failed to set window size: %w", err)
+	}
+	return nil
+}
+
+func (pty *Pty) CloseTty() error {
+	if err := pty.tty.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+func (pty *Pty) Close() error {
+	return errors.Join(pty.CloseTty(), pty.master.Close())
+}
+
+func MakeRaw(fd uintptr) (restore func() error, err error) {
+	var termios syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios))); err != nil {
+		return nil, fmt.Errorf("failed to get terminal attributes: %w", err)
+	}
+	saved := termios
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+	// Output processing stays enabled, so that newlines from the pty and logs still return the cursor
+	if err := ioctl(fd, syscall.TCSETS, uintptr(unsafe.Pointer(&termios))); err != nil {
+		return nil, fmt.Errorf("failed to set terminal attributes: %w", err)
+	}
+	return func() error {
+		if err := ioctl(fd, syscall.TCSETS, uintptr(unsafe.Pointer(&saved))); err != nil {
+			return fmt.Errorf("failed to restore terminal attributes: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+func ioctl(fd uintptr, request uintptr, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
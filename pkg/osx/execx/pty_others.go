@@ -0,0 +1,46 @@
+//go:build !linux
+
+package execx
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+)
+
+var ErrPtyNotSupported = errors.New("pty not supported")
+
+var ResizeSignals []os.Signal
+
+type Pty struct{}
+
+func OpenPty() (*Pty, error) {
+	return nil, ErrPtyNotSupported
+}
+
+func MakeRaw(fd uintptr) (restore func() error, err error) {
+	return nil, ErrPtyNotSupported
+}
+
+func (pty *Pty) Prepare(cmd *exec.Cmd) {}
+
+func (pty *Pty) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (pty *Pty) Write(p []byte) (int, error) {
+	return 0, ErrPtyNotSupported
+}
+
+func (pty *Pty) Resize(fd uintptr) error {
+	return ErrPtyNotSupported
+}
+
+func (pty *Pty) CloseTty() error {
+	return nil
+}
+
+func (pty *Pty) Close() error {
+	return nil
+}
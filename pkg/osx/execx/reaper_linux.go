@@ -0,0 +1,146 @@
+package execx
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	prSetChildSubreaper = 36
+
+	reaperInterval = time.Second
+)
+
+type Reaper struct {
+	mutex    *sync.Mutex
+	children map[int]struct{}
+	reaped   int
+	signals  chan os.Signal
+	done     chan struct{}
+	closed   chan struct{}
+}
+
+func NewReaper() (*Reaper, error) {
+	if os.Getpid() != 1 {
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0); errno != 0 {
+			return nil, fmt.Errorf("failed to set child subreaper: %w", errno)
+		}
+	}
+	r := &Reaper{
+		mutex:    &sync.Mutex{},
+		children: make(map[int]struct{}),
+		signals:  make(chan os.Signal, 1),
+		done:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	signal.Notify(r.signals, syscall.SIGCHLD)
+	go r.run()
+	return r, nil
+}
+
+func (r *Reaper) run() {
+	defer close(r.closed)
+	ticker := time.NewTicker(reaperInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			r.reap()
+			return
+		case <-r.signals:
+			r.reap()
+		case <-ticker.C:
+			r.reap()
+		}
+	}
+}
+
+func (r *Reaper) reap() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for {
+		pid, err := waitablePid()
+		if err != nil || pid <= 0 {
+			return
+		}
+		if _, ok := r.children[pid]; ok {
+			// Tracked children are reaped by exec.Cmd.Wait, so other zombies are found in proc
+			r.reapZombies()
+			return
+		}
+		if !r.reapPid(pid) {
+			return
+		}
+	}
+}
+
+func (r *Reaper) reapZombies() {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return
+	}
+	self := os.Getpid()
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if _, ok := r.children[pid]; ok {
+			continue
+		}
+		if stat, err := readProcStat(pid); err == nil && stat.ppid == self && stat.state == 'Z' {
+			r.reapPid(pid)
+		}
+	}
+}
+
+func (r *Reaper) reapPid(pid int) bool {
+	var status syscall.WaitStatus
+	if pid, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil); err != nil || pid <= 0 {
+		return false
+	}
+	r.reaped++
+	return true
+}
+
+func waitablePid() (int, error) {
+	return waitid(pAll, 0, syscall.WNOHANG|wExited|wNoWait)
+}
+
+func (r *Reaper) Start(cmd *exec.Cmd) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	r.children[cmd.Process.Pid] = struct{}{}
+	return nil
+}
+
+func (r *Reaper) Wait(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+	r.mutex.Lock()
+	delete(r.children, cmd.Process.Pid)
+	r.mutex.Unlock()
+	r.reap()
+	return err
+}
+
+func (r *Reaper) Reaped() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.reaped
+}
+
+func (r *Reaper) Close() error {
+	signal.Stop(r.signals)
+	close(r.done)
+	<-r.closed
+	return nil
+}
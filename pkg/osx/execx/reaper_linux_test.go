@@ -0,0 +1,40 @@
+package execx
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestReaperSkipsTrackedChildren(t *testing.T) {
+	r, err := NewReaper()
+	if err != nil {
+		t.Skipf("reaper unavailable: %v", err)
+	}
+	defer r.Close()
+	tracked := exec.Command("true")
+	if err := r.Start(tracked); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stat, err := readProcStat(tracked.Process.Pid); err == nil && stat.state == 'Z' {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	orphan := exec.Command("true")
+	if err := orphan.Start(); err != nil {
+		t.Fatal(err)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for r.Reaped() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reaped := r.Reaped(); reaped != 1 {
+		t.Errorf("expected 1 reaped process, got %v", reaped)
+	}
+	if err := r.Wait(tracked); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+package execx
+
+import (
+	"errors"
+	"os/exec"
+)
+
+var ErrReaperNotSupported = errors.New("reaper not supported")
+
+type Reaper struct{}
+
+func NewReaper() (*Reaper, error) {
+	return nil, ErrReaperNotSupported
+}
+
+func (r *Reaper) Start(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+func (r *Reaper) Wait(cmd *exec.Cmd) error {
+	return cmd.Wait()
+}
+
+func (r *Reaper) Reaped() int {
+	return 0
+}
+
+func (r *Reaper) Close() error {
+	return nil
+}
@@ -0,0 +1,82 @@
+package execx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownRlimit      = errors.New("unknown resource limit")
+	ErrRlimitNotSupported = errors.New("resource limits not supported")
+)
+
+const rlimitUnlimited = "unlimited"
+
+type Rlimit struct {
+	Name string
+	Soft uint64
+	Hard uint64
+}
+
+func (rlimit Rlimit) String() string {
+	return rlimit.Name + "=" + formatRlimitValue(rlimit.Soft) + ":" + formatRlimitValue(rlimit.Hard)
+}
+
+func ParseRlimit(s string) (Rlimit, error) {
+	name, values, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return Rlimit{}, fmt.Errorf("invalid resource limit format (%s)", s)
+	}
+	name = strings.ToLower(name)
+	if _, ok := rlimitResources[name]; !ok {
+		return Rlimit{}, fmt.Errorf("%w (%s)", ErrUnknownRlimit, name)
+	}
+	softValue, hardValue, ok := strings.Cut(values, ":")
+	if !ok {
+		hardValue = softValue
+	}
+	soft, err := parseRlimitValue(softValue)
+	if err != nil {
+		return Rlimit{}, fmt.Errorf("failed to parse soft limit of %s: %w", name, err)
+	}
+	hard, err := parseRlimitValue(hardValue)
+	if err != nil {
+		return Rlimit{}, fmt.Errorf("failed to parse hard limit of %s: %w", name, err)
+	}
+	if soft > hard {
+		return Rlimit{}, fmt.Errorf("soft limit of %s exceeds hard limit", name)
+	}
+	return Rlimit{Name: name, Soft: soft, Hard: hard}, nil
+}
+
+func ParseRlimits(s []string) ([]Rlimit, error) {
+	var rlimits []Rlimit
+	for _, value := range s {
+		rlimit, err := ParseRlimit(value)
+		if err != nil {
+			return nil, err
+		}
+		rlimits = append(rlimits, rlimit)
+	}
+	return rlimits, nil
+}
+
+func parseRlimitValue(s string) (uint64, error) {
+	if s == rlimitUnlimited || s == "-1" {
+		return rlimitInfinity, nil
+	}
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse value: %w", err)
+	}
+	return min(value, rlimitInfinity), nil
+}
+
+func formatRlimitValue(value uint64) string {
+	if value == rlimitInfinity {
+		return rlimitUnlimited
+	}
+	return strconv.FormatUint(value, 10)
+}
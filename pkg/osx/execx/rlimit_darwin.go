@@ -0,0 +1,5 @@
+package execx
+
+import "syscall"
+
+const rlimitInfinity = uint64(syscall.RLIM_INFINITY)
@@ -0,0 +1,3 @@
+package execx
+
+const rlimitInfinity = ^uint64(0)
@@ -0,0 +1,25 @@
+//go:build !darwin && !linux
+
+package execx
+
+import (
+	"os/exec"
+)
+
+const rlimitInfinity = ^uint64(0)
+
+var rlimitResources = map[string]int{
+	"as":     0,
+	"core":   0,
+	"cpu":    0,
+	"nofile": 0,
+}
+
+func CmdRlimits(cmd *exec.Cmd, rlimits []Rlimit) (*ExecStatus, error) {
+	if len(rlimits) == 0 {
+		return nil, nil
+	}
+	return nil, ErrRlimitNotSupported
+}
+
+func ExecRlimits() {}
@@ -0,0 +1,87 @@
+//go:build darwin || linux
+
+package execx
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	envExecPath    = "STDHTTP_EXEC_PATH"
+	envExecRlimits = "STDHTTP_EXEC_RLIMITS"
+	envExecStatus  = "STDHTTP_EXEC_STATUS_FD"
+)
+
+var rlimitResources = map[string]int{
+	"as":     syscall.RLIMIT_AS,
+	"core":   syscall.RLIMIT_CORE,
+	"cpu":    syscall.RLIMIT_CPU,
+	"nofile": syscall.RLIMIT_NOFILE,
+}
+
+func CmdRlimits(cmd *exec.Cmd, rlimits []Rlimit) (*ExecStatus, error) {
+	if len(rlimits) == 0 {
+		return nil, nil
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executable: %w", err)
+	}
+	status, err := newExecStatus()
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, rlimit := range rlimits {
+		values = append(values, rlimit.String())
+	}
+	fd := 3 + len(cmd.ExtraFiles)
+	cmd.ExtraFiles = append(cmd.ExtraFiles, status.w)
+	cmd.Env = append(cmd.Environ(), envExecPath+"="+cmd.Path, envExecRlimits+"="+strings.Join(values, ","), envExecStatus+"="+strconv.Itoa(fd))
+	cmd.Path = executable
+	return status, nil
+}
+
+func ExecRlimits() {
+	path, ok := os.LookupEnv(envExecPath)
+	if !ok {
+		return
+	}
+	var status *os.File
+	if fd, err := strconv.Atoi(os.Getenv(envExecStatus)); err == nil {
+		syscall.CloseOnExec(fd)
+		status = os.NewFile(uintptr(fd), "exec-status")
+	}
+	if err := execRlimits(path, os.Getenv(envExecRlimits)); err != nil {
+		if status != nil {
+			fmt.Fprint(status, err)
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(ExitCodeStartFailed)
+	}
+}
+
+func execRlimits(path string, values string) error {
+	os.Unsetenv(envExecPath)
+	os.Unsetenv(envExecRlimits)
+	os.Unsetenv(envExecStatus)
+	for _, value := range strings.Split(values, ",") {
+		rlimit, err := ParseRlimit(value)
+		if err != nil {
+			return err
+		}
+		if err := syscall.Setrlimit(rlimitResources[rlimit.Name], &syscall.Rlimit{Cur: rlimit.Soft, Max: rlimit.Hard}); err != nil {
+			return fmt.Errorf("failed to set resource limit %s: %w", rlimit.Name, err)
+		}
+	}
+	if err := syscall.Exec(path, os.Args, os.Environ()); err != nil {
+		return fmt.Errorf("failed to exec %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package execx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+const (
+	defaultClockTicks = 100
+	auxvClockTicks    = 17
+)
+
+var clockTicks = sync.OnceValue(func() int64 {
+	// USER_HZ is read from the auxiliary vector like sysconf(_SC_CLK_TCK) does, which is not available without cgo
+	data, err := os.ReadFile("/proc/self/auxv")
+	if err != nil {
+		return defaultClockTicks
+	}
+	size := int(unsafe.Sizeof(uintptr(0)))
+	word := func(b []byte) uint64 {
+		if size == 4 {
+			return uint64(binary.NativeEndian.Uint32(b))
+		}
+		return binary.NativeEndian.Uint64(b)
+	}
+	for i := 0; i+2*size <= len(data); i += 2 * size {
+		if word(data[i:]) == auxvClockTicks {
+			if ticks := int64(word(data[i+size:])); ticks > 0 {
+				return ticks
+			}
+		}
+	}
+	return defaultClockTicks
+})
+
+type procStat struct {
+	state byte
+	ppid  int
+	ticks int64
+	pages int64
+}
+
+func SampleTree(pid int) (Sample, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to read proc: %w", err)
+	}
+	stats := make(map[int]procStat)
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		child, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := readProcStat(child)
+		if err != nil {
+			continue
+		}
+		stats[child] = stat
+		children[stat.ppid] = append(children[stat.ppid], child)
+	}
+	if _, ok := stats[pid]; !ok {
+		return Sample{}, fmt.Errorf("failed to find process %d: %w", pid, os.ErrNotExist)
+	}
+	var sample Sample
+	pageSize := int64(os.Getpagesize())
+	for queue := []int{pid}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		stat := stats[current]
+		sample.Processes++
+		sample.CPUTime += time.Duration(stat.ticks) * time.Second / time.Duration(clockTicks())
+		sample.RSS += stat.pages * pageSize
+		if fds, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(current), "fd")); err == nil {
+			sample.OpenFDs += len(fds)
+		}
+		queue = append(queue, children[current]...)
+	}
+	return sample, nil
+}
+
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	// The command name may contain spaces and parentheses
+	index := strings.LastIndexByte(string(data), ')')
+	if index < 0 {
+		return procStat{}, fmt.Errorf("invalid stat format")
+	}
+	fields := strings.Fields(string(data[index+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("invalid stat format")
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	pages, _ := strconv.ParseInt(fields[21], 10, 64)
+	return procStat{state: fields[0][0], ppid: ppid, ticks: utime + stime, pages: pages}, nil
+}
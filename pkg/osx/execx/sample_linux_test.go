@@ -0,0 +1,56 @@
+package execx
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestSampleTree(t *testing.T) {
+	sample, err := SampleTree(os.Getpid())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sample.Processes < 1 {
+		t.Errorf("expected at least 1 process, got %v", sample.Processes)
+	}
+	if sample.RSS <= 0 {
+		t.Errorf("expected positive RSS, got %v", sample.RSS)
+	}
+	if sample.OpenFDs <= 0 {
+		t.Errorf("expected open file descriptors, got %v", sample.OpenFDs)
+	}
+}
+
+func TestSampleTreeChildren(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 5 & sleep 5 & wait")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+	var sample Sample
+	deadline := time.Now().Add(2 * time.Second)
+	for sample.Processes < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		var err error
+		if sample, err = SampleTree(cmd.Process.Pid); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sample.Processes != 3 {
+		t.Errorf("expected 3 processes, got %v", sample.Processes)
+	}
+	if sample.RSS <= 0 {
+		t.Errorf("expected positive RSS, got %v", sample.RSS)
+	}
+}
+
+func TestClockTicks(t *testing.T) {
+	if ticks := clockTicks(); ticks <= 0 || ticks > 10000 {
+		t.Errorf("expected a plausible clock tick rate, got %v", ticks)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package execx
+
+func SampleTree(pid int) (Sample, error) {
+	return Sample{}, ErrSampleNotSupported
+}
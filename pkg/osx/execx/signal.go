@@ -0,0 +1,34 @@
+package execx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var ErrUnknownSignal = errors.New("unknown signal")
+
+func SignalName(signal syscall.Signal) string {
+	for name, value := range signals {
+		if value == signal {
+			return name
+		}
+	}
+	return signal.String()
+}
+
+func ParseSignal(name string) (syscall.Signal, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if number, err := strconv.Atoi(name); err == nil && number > 0 {
+		return syscall.Signal(number), nil
+	}
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if signal, ok := signals[name]; ok {
+		return signal, nil
+	}
+	return 0, fmt.Errorf("%w (%s)", ErrUnknownSignal, name)
+}
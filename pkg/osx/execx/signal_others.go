@@ -0,0 +1,41 @@
+//go:build !windows
+
+package execx
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+var signals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGILL":  syscall.SIGILL,
+	"SIGTRAP": syscall.SIGTRAP,
+	"SIGABRT": syscall.SIGABRT,
+	"SIGBUS":  syscall.SIGBUS,
+	"SIGFPE":  syscall.SIGFPE,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGSEGV": syscall.SIGSEGV,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGPIPE": syscall.SIGPIPE,
+	"SIGALRM": syscall.SIGALRM,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// SIGINT and SIGTERM cancel the run, which sends the stop signal instead
+var ForwardSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2}
+
+func CmdStop(cmd *exec.Cmd, signal syscall.Signal, timeout time.Duration) {
+	if timeout <= 0 {
+		signal = syscall.SIGKILL
+	}
+	cmd.Cancel = func() error {
+		return Signal(cmd, signal)
+	}
+	cmd.WaitDelay = max(timeout, 0)
+}
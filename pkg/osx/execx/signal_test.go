@@ -0,0 +1,22 @@
+package execx
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"SIGTERM", "sigterm", "TERM", "15"} {
+		signal, err := ParseSignal(name)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", name, err)
+		}
+		if signal != syscall.SIGTERM {
+			t.Errorf("%v: expected %v, got %v", name, syscall.SIGTERM, signal)
+		}
+	}
+	if _, err := ParseSignal("SIGNOPE"); !errors.Is(err, ErrUnknownSignal) {
+		t.Errorf("expected %v, got %v", ErrUnknownSignal, err)
+	}
+}
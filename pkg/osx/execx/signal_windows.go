@@ -0,0 +1,20 @@
+package execx
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+var signals = map[string]syscall.Signal{
+	"SIGINT":  syscall.SIGINT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+var ForwardSignals []os.Signal
+
+func CmdStop(cmd *exec.Cmd, signal syscall.Signal, timeout time.Duration) {
+	// Windows processes can only be killed
+}
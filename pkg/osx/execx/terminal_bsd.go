@@ -0,0 +1,41 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package execx
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+const terminalJobControl = false
+
+func IsTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGETA, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
+
+func foregroundProcessGroup(fd uintptr) (int, error) {
+	var pgid int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgid))); errno != 0 {
+		return 0, errno
+	}
+	return int(pgid), nil
+}
+
+func setForegroundProcessGroup(fd uintptr, pgid int) error {
+	value := int32(pgid)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCSPGRP, uintptr(unsafe.Pointer(&value))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func processStopped(pid int) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+func orphanedProcessGroup() bool {
+	return true
+}
@@ -0,0 +1,50 @@
+package execx
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const terminalJobControl = true
+
+func IsTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
+
+func foregroundProcessGroup(fd uintptr) (int, error) {
+	var pgid int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgid))); errno != 0 {
+		return 0, errno
+	}
+	return int(pgid), nil
+}
+
+func setForegroundProcessGroup(fd uintptr, pgid int) error {
+	value := int32(pgid)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCSPGRP, uintptr(unsafe.Pointer(&value))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func processStopped(pid int) (bool, error) {
+	// Stopped children are reported without waiting for exited ones, which exec.Cmd.Wait reaps
+	stopped, err := waitid(pPid, pid, syscall.WNOHANG|wStopped)
+	return stopped == pid, err
+}
+
+func orphanedProcessGroup() bool {
+	ppid := syscall.Getppid()
+	pgid, err := syscall.Getpgid(ppid)
+	if err != nil {
+		return true
+	}
+	sid, _, errno := syscall.RawSyscall(syscall.SYS_GETSID, 0, 0, 0)
+	if errno != 0 {
+		return true
+	}
+	parentSid, _, errno := syscall.RawSyscall(syscall.SYS_GETSID, uintptr(ppid), 0, 0)
+	return errno != 0 || pgid == syscall.Getpgrp() || parentSid != sid
+}
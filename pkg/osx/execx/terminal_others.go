@@ -0,0 +1,29 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package execx
+
+import (
+	"errors"
+)
+
+const terminalJobControl = false
+
+func IsTerminal(fd uintptr) bool {
+	return false
+}
+
+func foregroundProcessGroup(fd uintptr) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func setForegroundProcessGroup(fd uintptr, pgid int) error {
+	return errors.ErrUnsupported
+}
+
+func processStopped(pid int) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+func orphanedProcessGroup() bool {
+	return true
+}
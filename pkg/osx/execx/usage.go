@@ -0,0 +1,23 @@
+package execx
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSampleNotSupported = errors.New("process sampling not supported")
+
+type Usage struct {
+	UserTime                   time.Duration
+	SystemTime                 time.Duration
+	MaxRSS                     int64
+	VoluntaryContextSwitches   int64
+	InvoluntaryContextSwitches int64
+}
+
+type Sample struct {
+	Processes int
+	CPUTime   time.Duration
+	RSS       int64
+	OpenFDs   int
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package execx
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+func ProcessUsage(state *os.ProcessState) Usage {
+	if state == nil {
+		return Usage{}
+	}
+	usage := Usage{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok && rusage != nil {
+		usage.MaxRSS = int64(rusage.Maxrss)
+		if runtime.GOOS != "darwin" {
+			// Reported in kilobytes everywhere but macOS
+			usage.MaxRSS *= 1024
+		}
+		usage.VoluntaryContextSwitches = int64(rusage.Nvcsw)
+		usage.InvoluntaryContextSwitches = int64(rusage.Nivcsw)
+	}
+	return usage
+}
@@ -0,0 +1,15 @@
+package execx
+
+import (
+	"os"
+)
+
+func ProcessUsage(state *os.ProcessState) Usage {
+	if state == nil {
+		return Usage{}
+	}
+	return Usage{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+}
@@ -0,0 +1,25 @@
+package execx
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	pAll     = 0
+	pPid     = 1
+	wStopped = 0x2
+	wExited  = 0x4
+	wNoWait  = 0x1000000
+)
+
+func waitid(idtype int, id int, options int) (int, error) {
+	var info [128]byte
+	_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, uintptr(idtype), uintptr(id), uintptr(unsafe.Pointer(&info[0])), uintptr(options), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	// si_pid follows si_signo, si_errno and si_code aligned to the pointer size
+	offset := (12 + unsafe.Sizeof(uintptr(0)) - 1) &^ (unsafe.Sizeof(uintptr(0)) - 1)
+	return int(*(*int32)(unsafe.Pointer(&info[offset]))), nil
+}
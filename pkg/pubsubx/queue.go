@@ -0,0 +1,137 @@
+package pubsubx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mainden/stdhttp/pkg/logx"
+)
+
+var ErrQueueClosed = errors.New("queue closed")
+
+type QueuePolicy string
+
+const (
+	QueuePolicyBlock      QueuePolicy = "block"
+	QueuePolicyDropOldest QueuePolicy = "drop-oldest"
+	QueuePolicyDropNewest QueuePolicy = "drop-newest"
+	QueuePolicySample     QueuePolicy = "sample"
+)
+
+type queueMessage struct {
+	ctx     context.Context
+	message interface{}
+}
+
+type queue struct {
+	handler    Handler
+	size       int
+	policy     QueuePolicy
+	sampleRate int
+	messages   []queueMessage
+	sampled    int
+	closed     bool
+	dropped    atomic.Uint64
+	cond       *sync.Cond
+	done       chan struct{}
+}
+
+func NewQueue(handler Handler, size int, policy QueuePolicy, sampleRate int) *queue {
+	if size <= 0 {
+		size = 1
+	}
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+	q := &queue{
+		handler:    handler,
+		size:       size,
+		policy:     policy,
+		sampleRate: sampleRate,
+		cond:       sync.NewCond(&sync.Mutex{}),
+		done:       make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *queue) Handle(ctx context.Context, message interface{}) error {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.policy == QueuePolicyBlock && len(q.messages) >= q.size && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if len(q.messages) >= q.size {
+		switch q.policy {
+		case QueuePolicyDropOldest:
+			q.messages = q.messages[1:]
+		case QueuePolicySample:
+			q.sampled++
+			if q.sampled%q.sampleRate != 0 {
+				q.dropped.Add(1)
+				return nil
+			}
+			q.messages = q.messages[1:]
+		default:
+			q.dropped.Add(1)
+			return nil
+		}
+		q.dropped.Add(1)
+	}
+	q.messages = append(q.messages, queueMessage{ctx: ctx, message: message})
+	q.cond.Broadcast()
+	return nil
+}
+
+func (q *queue) next() (queueMessage, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.messages) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if len(q.messages) == 0 {
+		return queueMessage{}, false
+	}
+	message := q.messages[0]
+	q.messages[0] = queueMessage{}
+	q.messages = q.messages[1:]
+	q.cond.Broadcast()
+	return message, true
+}
+
+func (q *queue) run() {
+	defer close(q.done)
+	for {
+		message, ok := q.next()
+		if !ok {
+			return
+		}
+		if err := q.handler.Handle(message.ctx, message.message); err != nil {
+			logx.DebugContext(logx.WithName(message.ctx, "pubsub_queue"), "Failed to handle queued message", "handler", GetHandlerName(q.handler), "error", err)
+		}
+	}
+}
+
+func (q *queue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.messages)
+}
+
+func (q *queue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+func (q *queue) Close() error {
+	q.cond.L.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.cond.L.Unlock()
+	<-q.done
+	return nil
+}
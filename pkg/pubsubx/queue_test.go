@@ -0,0 +1,80 @@
+package pubsubx
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func collectQueue(t *testing.T, policy QueuePolicy, sampleRate int, messages ...string) ([]string, uint64) {
+	var mutex sync.Mutex
+	var received []string
+	started := make(chan struct{})
+	release := make(chan struct{})
+	q := NewQueue(HandlerFunc(func(ctx context.Context, message interface{}) error {
+		if message == "first" {
+			close(started)
+			<-release
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		received = append(received, message.(string))
+		return nil
+	}), 2, policy, sampleRate)
+	if err := q.Handle(context.Background(), "first"); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	for _, message := range messages {
+		if err := q.Handle(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(release)
+	q.Close()
+	if err := q.Handle(context.Background(), "closed"); err != ErrQueueClosed {
+		t.Error("expected ErrQueueClosed")
+	}
+	return received, q.Dropped()
+}
+
+func TestQueueDropNewest(t *testing.T) {
+	received, dropped := collectQueue(t, QueuePolicyDropNewest, 0, "a", "b", "c", "d")
+	if !slices.Equal(received, []string{"first", "a", "b"}) {
+		t.Errorf("unexpected messages: %v", received)
+	}
+	if dropped != 2 {
+		t.Errorf("expected 2 dropped, got %v", dropped)
+	}
+}
+
+func TestQueueDropOldest(t *testing.T) {
+	received, dropped := collectQueue(t, QueuePolicyDropOldest, 0, "a", "b", "c", "d")
+	if !slices.Equal(received, []string{"first", "c", "d"}) {
+		t.Errorf("unexpected messages: %v", received)
+	}
+	if dropped != 2 {
+		t.Errorf("expected 2 dropped, got %v", dropped)
+	}
+}
+
+func TestQueueSample(t *testing.T) {
+	received, dropped := collectQueue(t, QueuePolicySample, 2, "a", "b", "c", "d", "e", "f")
+	if !slices.Equal(received, []string{"first", "d", "f"}) {
+		t.Errorf("unexpected messages: %v", received)
+	}
+	if dropped != 4 {
+		t.Errorf("expected 4 dropped, got %v", dropped)
+	}
+}
+
+func TestQueueBlock(t *testing.T) {
+	received, dropped := collectQueue(t, QueuePolicyBlock, 0, "a", "b")
+	if !slices.Equal(received, []string{"first", "a", "b"}) {
+		t.Errorf("unexpected messages: %v", received)
+	}
+	if dropped != 0 {
+		t.Errorf("expected 0 dropped, got %v", dropped)
+	}
+}
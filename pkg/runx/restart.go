@@ -0,0 +1,74 @@
+package runx
+
+import (
+	"time"
+)
+
+type RestartPolicy string
+
+const (
+	RestartAlways    RestartPolicy = "always"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartNever     RestartPolicy = "never"
+)
+
+type RestartOptions struct {
+	Policy      RestartPolicy
+	MinDelay    time.Duration
+	MaxDelay    time.Duration
+	MaxRestarts int
+	Window      time.Duration
+	ResetAfter  time.Duration
+}
+
+type restarter struct {
+	options  RestartOptions
+	backoff  int
+	restarts []time.Time
+}
+
+func NewRestarter(options RestartOptions) *restarter {
+	if options.MaxDelay < options.MinDelay {
+		options.MaxDelay = options.MinDelay
+	}
+	return &restarter{
+		options: options,
+	}
+}
+
+func (r *restarter) ShouldRestart(failed bool) bool {
+	switch r.options.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return failed
+	}
+	return false
+}
+
+func (r *restarter) LimitReached(now time.Time) bool {
+	if r.options.MaxRestarts <= 0 {
+		return false
+	}
+	if r.options.Window > 0 {
+		i := 0
+		for i < len(r.restarts) && now.Sub(r.restarts[i]) >= r.options.Window {
+			i++
+		}
+		r.restarts = r.restarts[i:]
+	}
+	return len(r.restarts) >= r.options.MaxRestarts
+}
+
+func (r *restarter) Next(now time.Time, uptime time.Duration) time.Duration {
+	if r.options.ResetAfter > 0 && uptime >= r.options.ResetAfter {
+		r.backoff = 0
+	}
+	delay := r.options.MaxDelay
+	if r.backoff < 32 {
+		delay = min(r.options.MinDelay<<r.backoff, r.options.MaxDelay)
+	}
+	r.backoff++
+	r.restarts = append(r.restarts, now)
+	return delay
+}
@@ -0,0 +1,54 @@
+package runx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestarterShouldRestart(t *testing.T) {
+	tests := []struct {
+		policy RestartPolicy
+		failed bool
+		want   bool
+	}{
+		{RestartAlways, false, true},
+		{RestartAlways, true, true},
+		{RestartOnFailure, false, false},
+		{RestartOnFailure, true, true},
+		{RestartNever, true, false},
+	}
+	for _, test := range tests {
+		if got := NewRestarter(RestartOptions{Policy: test.policy}).ShouldRestart(test.failed); got != test.want {
+			t.Errorf("policy %v failed %v: expected %v, got %v", test.policy, test.failed, test.want, got)
+		}
+	}
+}
+
+func TestRestarterBackoff(t *testing.T) {
+	r := NewRestarter(RestartOptions{Policy: RestartAlways, MinDelay: time.Second, MaxDelay: 5 * time.Second, ResetAfter: time.Minute})
+	now := time.Now()
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := r.Next(now, 0); got != want {
+			t.Errorf("restart %v: expected delay %v, got %v", i, want, got)
+		}
+	}
+	if got := r.Next(now, time.Minute); got != time.Second {
+		t.Errorf("expected delay to reset to %v, got %v", time.Second, got)
+	}
+}
+
+func TestRestarterLimit(t *testing.T) {
+	r := NewRestarter(RestartOptions{Policy: RestartAlways, MaxRestarts: 2, Window: time.Minute})
+	now := time.Now()
+	r.Next(now, 0)
+	if r.LimitReached(now) {
+		t.Fatal("expected limit not reached after one restart")
+	}
+	r.Next(now.Add(time.Second), 0)
+	if !r.LimitReached(now.Add(2 * time.Second)) {
+		t.Fatal("expected limit reached after two restarts")
+	}
+	if r.LimitReached(now.Add(time.Minute + time.Second)) {
+		t.Fatal("expected limit not reached after the window passed")
+	}
+}
@@ -0,0 +1,13 @@
+package slicesx
+
+func Distinct[S ~[]E, E comparable](s S) S {
+	var result S
+	seen := make(map[E]struct{}, len(s))
+	for _, e := range s {
+		if _, ok := seen[e]; !ok {
+			seen[e] = struct{}{}
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package spoolx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	fileExt     = ".spool"
+	tempFileExt = ".tmp"
+)
+
+var ErrSpoolFull = errors.New("spool full")
+
+type spoolFile struct {
+	name string
+	size int64
+}
+
+type spool struct {
+	dir     string
+	maxSize int64
+	next    uint64
+	files   []spoolFile
+	size    int64
+	mutex   *sync.Mutex
+}
+
+func Open(dir string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	s := &spool{
+		dir:     dir,
+		maxSize: maxSize,
+		mutex:   &sync.Mutex{},
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if strings.HasSuffix(name, tempFileExt) {
+			_ = os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		seq, ok := parseFileName(name)
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat spool file: %w", err)
+		}
+		s.files = append(s.files, spoolFile{name: name, size: info.Size()})
+		s.size += info.Size()
+		if seq >= s.next {
+			s.next = seq + 1
+		}
+	}
+	sort.Slice(s.files, func(i, j int) bool { return s.files[i].name < s.files[j].name })
+	return s, nil
+}
+
+func formatFileName(seq uint64) string {
+	return fmt.Sprintf("%020d%s", seq, fileExt)
+}
+
+func parseFileName(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, fileExt) {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(strings.TrimSuffix(name, fileExt), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+func (s *spool) Dir() string {
+	return s.dir
+}
+
+func (s *spool) Push(data []byte) (evicted int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	size := int64(len(data))
+	if s.maxSize > 0 && size > s.maxSize {
+		return 0, fmt.Errorf("%w (%d bytes exceeds limit of %d bytes)", ErrSpoolFull, size, s.maxSize)
+	}
+	for s.maxSize > 0 && s.size+size > s.maxSize && len(s.files) > 0 {
+		if err := s.remove(s.files[0].name); err != nil {
+			return evicted, err
+		}
+		evicted++
+	}
+
+	name := formatFileName(s.next)
+	path := filepath.Join(s.dir, name)
+	if err := writeFile(path+tempFileExt, data); err != nil {
+		return evicted, err
+	}
+	if err := os.Rename(path+tempFileExt, path); err != nil {
+		_ = os.Remove(path + tempFileExt)
+		return evicted, fmt.Errorf("failed to commit spool file: %w", err)
+	}
+	s.next++
+	s.files = append(s.files, spoolFile{name: name, size: size})
+	s.size += size
+	return evicted, nil
+}
+
+func writeFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create spool file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write spool file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync spool file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close spool file: %w", err)
+	}
+	return nil
+}
+
+func (s *spool) Peek() (name string, data []byte, ok bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.files) == 0 {
+		return "", nil, false, nil
+	}
+	name = s.files[0].name
+	data, err = os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return name, nil, true, fmt.Errorf("failed to read spool file: %w", err)
+	}
+	return name, data, true, nil
+}
+
+func (s *spool) remove(name string) error {
+	index := -1
+	for i, file := range s.files {
+		if file.name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spool file: %w", err)
+	}
+	s.size -= s.files[index].size
+	s.files = append(s.files[:index], s.files[index+1:]...)
+	return nil
+}
+
+func (s *spool) Remove(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.remove(name)
+}
+
+func (s *spool) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.files)
+}
+
+func (s *spool) Size() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size
+}
@@ -0,0 +1,47 @@
+package spoolx
+
+import (
+	"testing"
+)
+
+func TestSpool(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range []string{"aaaa", "bbbb", "cccc"} {
+		if _, err := s.Push([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Len() != 2 {
+		t.Errorf("expected 2 entries, got %v", s.Len())
+	}
+	if _, err := s.Push([]byte("too large value")); err == nil {
+		t.Error("expected ErrSpoolFull")
+	}
+
+	s, err = Open(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 8 {
+		t.Errorf("expected size 8, got %v", s.Size())
+	}
+	for _, expected := range []string{"bbbb", "cccc"} {
+		name, data, ok, err := s.Peek()
+		if err != nil || !ok {
+			t.Fatal("expected entry", err)
+		}
+		if string(data) != expected {
+			t.Errorf("expected %v, got %v", expected, string(data))
+		}
+		if err := s.Remove(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, ok, _ := s.Peek(); ok {
+		t.Error("expected empty spool")
+	}
+}
@@ -0,0 +1,84 @@
+package textx
+
+import (
+	"io"
+)
+
+const (
+	ansiText = iota
+	ansiEscape
+	ansiIntermediate
+	ansiCSI
+	ansiString
+	ansiStringEscape
+)
+
+type ansiWriter struct {
+	w     io.Writer
+	state int
+}
+
+func NewAnsiWriter(w io.Writer) *ansiWriter {
+	return &ansiWriter{
+		w: w,
+	}
+}
+
+func (w *ansiWriter) Write(p []byte) (n int, err error) {
+	buf := make([]byte, 0, len(p))
+	for _, c := range p {
+		switch w.state {
+		case ansiText:
+			if c == 0x1b {
+				w.state = ansiEscape
+			} else {
+				buf = append(buf, c)
+			}
+		case ansiEscape:
+			switch {
+			case c == '[':
+				w.state = ansiCSI
+			case c == ']' || c == 'P' || c == 'X' || c == '^' || c == '_':
+				w.state = ansiString
+			case c >= 0x20 && c <= 0x2f:
+				w.state = ansiIntermediate
+			default:
+				w.state = ansiText
+			}
+		case ansiIntermediate, ansiCSI:
+			// Control characters end malformed sequences, so that the following text is not lost
+			switch {
+			case c == 0x1b:
+				w.state = ansiEscape
+			case c < 0x20:
+				w.state = ansiText
+				buf = append(buf, c)
+			case w.state == ansiIntermediate && c > 0x2f:
+				w.state = ansiText
+			case w.state == ansiCSI && c >= 0x40 && c <= 0x7e:
+				w.state = ansiText
+			}
+		case ansiString:
+			// Strings end with BEL or ST (ESC \), or are abandoned at a newline
+			switch c {
+			case 0x07:
+				w.state = ansiText
+			case 0x1b:
+				w.state = ansiStringEscape
+			case '\n':
+				w.state = ansiText
+				buf = append(buf, c)
+			}
+		case ansiStringEscape:
+			if c == '\\' {
+				w.state = ansiText
+			} else if c != 0x1b {
+				w.state = ansiString
+			}
+		}
+	}
+	if _, err := w.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
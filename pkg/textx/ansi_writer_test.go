@@ -0,0 +1,36 @@
+package textx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnsiWriter(t *testing.T) {
+	tests := []struct {
+		chunks []string
+		want   string
+	}{
+		{[]string{"plain text\n"}, "plain text\n"},
+		{[]string{"\x1b[1;31mred\x1b[0m\n"}, "red\n"},
+		{[]string{"\x1b[", "32", "mgreen\x1b", "[0m"}, "green"},
+		{[]string{"\x1b]0;title\x07text"}, "text"},
+		{[]string{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\"}, "link"},
+		{[]string{"\x1b(Bcharset\x1b=keypad"}, "charsetkeypad"},
+		{[]string{"\x1b[12\nnext"}, "\nnext"},
+		{[]string{"\x1b[1", "\r\x1b[0mdone"}, "\rdone"},
+		{[]string{"\x1b(\tindent"}, "\tindent"},
+		{[]string{"\x1b]0;title\nnext"}, "\nnext"},
+	}
+	for _, test := range tests {
+		var builder strings.Builder
+		w := NewAnsiWriter(&builder)
+		for _, chunk := range test.chunks {
+			if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+				t.Fatalf("failed to write %q: n %v, error %v", chunk, n, err)
+			}
+		}
+		if got := builder.String(); got != test.want {
+			t.Errorf("chunks %q: expected %q, got %q", test.chunks, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package textx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const EncodingBase64 = "base64"
+
+var lineSeq atomic.Uint64
+
+type Line struct {
+	Text      string
+	Time      time.Time
+	Seq       uint64
+	Encoding  string
+	Continued bool
+	Truncated bool
+}
+
+func MakeLine(text string) Line {
+	return Line{
+		Text: text,
+		Time: time.Now(),
+		Seq:  lineSeq.Add(1),
+	}
+}
@@ -0,0 +1,134 @@
+package textx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainden/stdhttp/pkg/logx"
+	"github.com/mainden/stdhttp/pkg/pubsubx"
+)
+
+type textBatcher struct {
+	handler       pubsubx.Handler
+	maxItems      int
+	maxBytes      int
+	flushInterval time.Duration
+	items         []Line
+	size          int
+	timer         *time.Timer
+	mutex         *sync.Mutex
+	sending       *sync.Mutex
+}
+
+func NewTextBatcher(handler pubsubx.Handler, maxItems int, maxBytes int, flushInterval time.Duration) *textBatcher {
+	if maxItems <= 0 {
+		maxItems = 1
+	}
+	return &textBatcher{
+		handler:       handler,
+		maxItems:      maxItems,
+		maxBytes:      maxBytes,
+		flushInterval: flushInterval,
+		mutex:         &sync.Mutex{},
+		sending:       &sync.Mutex{},
+	}
+}
+
+func (b *textBatcher) Handle(ctx context.Context, message interface{}) error {
+	var messages []Line
+	switch message := message.(type) {
+	case string:
+		messages = []Line{MakeLine(message)}
+	case []string:
+		for _, message := range message {
+			messages = append(messages, MakeLine(message))
+		}
+	case Line:
+		messages = []Line{message}
+	case []Line:
+		messages = message
+	default:
+		return fmt.Errorf("%w (%T)", pubsubx.ErrUnexpectedMessageType, message)
+	}
+
+	b.mutex.Lock()
+	var batches [][]Line
+	for _, message := range messages {
+		if len(b.items) > 0 && b.maxBytes > 0 && b.size+len(message.Text) > b.maxBytes {
+			batches = append(batches, b.take())
+		}
+		b.items = append(b.items, message)
+		b.size += len(message.Text)
+		if len(b.items) >= b.maxItems || (b.maxBytes > 0 && b.size >= b.maxBytes) {
+			batches = append(batches, b.take())
+			continue
+		}
+		if b.timer == nil && b.flushInterval > 0 {
+			b.timer = time.AfterFunc(b.flushInterval, b.flushOnTimer)
+		}
+	}
+	if len(batches) == 0 {
+		b.mutex.Unlock()
+		return nil
+	}
+	// Batches are sent outside of the lock, but in the order they were taken
+	b.sending.Lock()
+	b.mutex.Unlock()
+	defer b.sending.Unlock()
+	return b.send(ctx, batches...)
+}
+
+func (b *textBatcher) flushOnTimer() {
+	ctx := logx.SetEvent(logx.WithName(context.Background(), "text_batcher"), "text_batcher")
+	b.mutex.Lock()
+	if !b.sending.TryLock() {
+		// A batch is being sent, so try again later instead of blocking new lines
+		b.timer = time.AfterFunc(b.flushInterval, b.flushOnTimer)
+		b.mutex.Unlock()
+		return
+	}
+	batch := b.take()
+	b.mutex.Unlock()
+	defer b.sending.Unlock()
+	if err := b.send(ctx, batch); err != nil {
+		logx.DebugContext(ctx, "Failed to flush batch", "error", err)
+	}
+}
+
+func (b *textBatcher) take() []Line {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	items := b.items
+	b.items = nil
+	b.size = 0
+	return items
+}
+
+func (b *textBatcher) send(ctx context.Context, batches ...[]Line) error {
+	var errs error
+	for _, batch := range batches {
+		if len(batch) > 0 {
+			errs = errors.Join(errs, b.handler.Handle(ctx, batch))
+		}
+	}
+	return errs
+}
+
+func (b *textBatcher) Flush(ctx context.Context) error {
+	b.mutex.Lock()
+	batch := b.take()
+	b.sending.Lock()
+	b.mutex.Unlock()
+	defer b.sending.Unlock()
+	return b.send(ctx, batch)
+}
+
+func (b *textBatcher) Close() error {
+	ctx := logx.SetEvent(logx.WithName(context.Background(), "text_batcher"), "text_batcher")
+	return b.Flush(ctx)
+}
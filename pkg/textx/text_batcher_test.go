@@ -0,0 +1,122 @@
+package textx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type batchRecorder struct {
+	mutex   sync.Mutex
+	batches [][]Line
+	block   chan struct{}
+}
+
+func (r *batchRecorder) Handle(ctx context.Context, message interface{}) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.batches = append(r.batches, message.([]Line))
+	return nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var sizes []int
+	for _, batch := range r.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func equalSizes(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTextBatcherMaxItems(t *testing.T) {
+	recorder := &batchRecorder{}
+	batcher := NewTextBatcher(recorder, 2, 0, 0)
+	for _, text := range []string{"a", "b", "c", "d", "e"} {
+		if err := batcher.Handle(context.Background(), text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := recorder.sizes(); !equalSizes(got, []int{2, 2}) {
+		t.Errorf("expected batches [2 2], got %v", got)
+	}
+	if err := batcher.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorder.sizes(); !equalSizes(got, []int{2, 2, 1}) {
+		t.Errorf("expected batches [2 2 1] after close, got %v", got)
+	}
+}
+
+func TestTextBatcherMaxBytes(t *testing.T) {
+	recorder := &batchRecorder{}
+	batcher := NewTextBatcher(recorder, 100, 10, 0)
+	for _, text := range []string{"aaaa", "bbbb", "cccc", "dddddddddd", "e"} {
+		if err := batcher.Handle(context.Background(), text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batcher.Close()
+	if got := recorder.sizes(); !equalSizes(got, []int{2, 1, 1, 1}) {
+		t.Errorf("expected batches [2 1 1 1], got %v", got)
+	}
+}
+
+func TestTextBatcherFlushInterval(t *testing.T) {
+	recorder := &batchRecorder{}
+	batcher := NewTextBatcher(recorder, 100, 0, 10*time.Millisecond)
+	defer batcher.Close()
+	batcher.Handle(context.Background(), []string{"a", "b"})
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.sizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := recorder.sizes(); !equalSizes(got, []int{2}) {
+		t.Errorf("expected batches [2] after the interval, got %v", got)
+	}
+}
+
+func TestTextBatcherSlowHandler(t *testing.T) {
+	recorder := &batchRecorder{block: make(chan struct{})}
+	batcher := NewTextBatcher(recorder, 2, 0, 10*time.Millisecond)
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		batcher.Handle(context.Background(), []string{"a", "b"})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		batcher.Handle(context.Background(), "c")
+	}()
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("expected lines to be added while the handler is blocked")
+	}
+	// The interval flush must not block either and retries after the send
+	time.Sleep(30 * time.Millisecond)
+	close(recorder.block)
+	<-sent
+	batcher.Close()
+	if got := recorder.sizes(); !equalSizes(got, []int{2, 1}) {
+		t.Errorf("expected batches [2 1], got %v", got)
+	}
+}
@@ -2,38 +2,130 @@ package textx
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"io"
+	"unicode/utf8"
 
 	"github.com/mainden/stdhttp/pkg/logx"
 	"github.com/mainden/stdhttp/pkg/pubsubx"
 )
 
+type LinePolicy string
+
+const (
+	LineSplit    LinePolicy = "split"
+	LineTruncate LinePolicy = "truncate"
+	LineGrow     LinePolicy = "grow"
+)
+
+const (
+	DefaultMaxLineSize = bufio.MaxScanTokenSize
+	TruncatedMarker    = "[truncated]"
+	// Grown lines are split above this multiple of the maximum size to bound memory
+	GrowMaxLineFactor = 16
+)
+
+type TextScannerOptions struct {
+	MaxLineSize int
+	LinePolicy  LinePolicy
+	Binary      bool
+}
+
 type textScanner struct {
-	reader io.Reader
-	topic  string
+	reader  io.Reader
+	topic   string
+	options TextScannerOptions
 }
 
-func NewTextScanner(reader io.Reader, topic string) *textScanner {
+func NewTextScanner(reader io.Reader, topic string, options TextScannerOptions) *textScanner {
+	if options.MaxLineSize <= 0 {
+		options.MaxLineSize = DefaultMaxLineSize
+	}
+	if options.LinePolicy == "" {
+		options.LinePolicy = LineSplit
+	}
 	return &textScanner{
-		reader: reader,
-		topic:  topic,
+		reader:  reader,
+		topic:   topic,
+		options: options,
 	}
 }
 
 func (s *textScanner) Run(ctx context.Context) {
 	ctx = logx.WithName(ctx, "text_scanner")
-	scanner := bufio.NewScanner(s.reader)
+	reader := bufio.NewReader(s.reader)
 	logx.DebugContext(ctx, "Running", "topic", s.topic)
 
-	for scanner.Scan() {
-		ctx := logx.SetEvent(ctx, "text_scanner")
-		if err := pubsubx.Publish(ctx, s.topic, scanner.Text()); err != nil {
-			logx.DebugContext(ctx, "Failed to publish message", "topic", s.topic, "error", err)
-		}
+	maxLineSize, policy := s.options.MaxLineSize, s.options.LinePolicy
+	if policy == LineGrow {
+		maxLineSize, policy = maxLineSize*GrowMaxLineFactor, LineSplit
 	}
-	if err := scanner.Err(); err != nil {
-		logx.DebugContext(ctx, "Failed to scan text", "topic", s.topic, "error", err)
+	var line []byte
+	discard := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !discard {
+			line = append(line, chunk...)
+		}
+		complete := !errors.Is(err, bufio.ErrBufferFull)
+		if complete && err == nil {
+			line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte{'\n'}), []byte{'\r'})
+		}
+		for !discard && len(line) > maxLineSize {
+			if policy == LineTruncate {
+				// Room is reserved for the marker so that truncated lines stay within the maximum size
+				cut := cutLine(line, max(maxLineSize-len(TruncatedMarker), 0))
+				s.publish(ctx, line[:cut], false, true)
+				line, discard = line[:0], true
+			} else {
+				cut := cutLine(line, maxLineSize)
+				s.publish(ctx, line[:cut], true, false)
+				line = append(line[:0], line[cut:]...)
+			}
+		}
+		if !complete {
+			continue
+		}
+		if !discard && (err == nil || len(line) > 0) {
+			s.publish(ctx, line, false, false)
+		}
+		line, discard = line[:0], false
+		if err != nil {
+			if err != io.EOF {
+				logx.DebugContext(ctx, "Failed to scan text", "topic", s.topic, "error", err)
+			}
+			break
+		}
 	}
 	logx.DebugContext(ctx, "Stopped", "topic", s.topic)
 }
+
+func (s *textScanner) publish(ctx context.Context, text []byte, continued bool, truncated bool) {
+	ctx = logx.SetEvent(ctx, "text_scanner")
+	line := MakeLine(string(text))
+	if s.options.Binary && !utf8.Valid(text) {
+		line.Text = base64.StdEncoding.EncodeToString(text)
+		line.Encoding = EncodingBase64
+	}
+	if truncated && line.Encoding == "" {
+		line.Text += TruncatedMarker
+	}
+	line.Continued = continued
+	line.Truncated = truncated
+	if err := pubsubx.Publish(ctx, s.topic, line); err != nil {
+		logx.DebugContext(ctx, "Failed to publish message", "topic", s.topic, "error", err)
+	}
+}
+
+func cutLine(line []byte, size int) int {
+	// Split at a rune boundary so that valid text stays valid in every chunk
+	for cut := size; cut > 0 && cut > size-utf8.UTFMax; cut-- {
+		if utf8.RuneStart(line[cut]) {
+			return cut
+		}
+	}
+	return size
+}
@@ -0,0 +1,110 @@
+package textx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mainden/stdhttp/pkg/pubsubx"
+)
+
+func scanLines(t *testing.T, input string, options TextScannerOptions) []Line {
+	t.Helper()
+	var lines []Line
+	ctx := pubsubx.WithManager(context.Background(), pubsubx.NewManager())
+	pubsubx.Subscribe(ctx, "lines", pubsubx.HandlerFunc(func(ctx context.Context, message interface{}) error {
+		lines = append(lines, message.(Line))
+		return nil
+	}))
+	NewTextScanner(strings.NewReader(input), "lines", options).Run(ctx)
+	return lines
+}
+
+func TestTextScannerSplit(t *testing.T) {
+	long := strings.Repeat("a", 10000)
+	lines := scanLines(t, "first\r\n\n"+long+"\nlast", TextScannerOptions{MaxLineSize: 4096})
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	want := []string{"first", "", long[:4096], long[4096:8192], long[8192:], "last"}
+	if strings.Join(texts, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %d lines %q, got %d lines", len(want), want, len(texts))
+	}
+	for i, continued := range []bool{false, false, true, true, false, false} {
+		if lines[i].Continued != continued {
+			t.Errorf("line %d: expected continued %v, got %v", i, continued, lines[i].Continued)
+		}
+	}
+}
+
+func TestTextScannerSplitRunes(t *testing.T) {
+	lines := scanLines(t, strings.Repeat("é", 3000), TextScannerOptions{MaxLineSize: 4095})
+	for i, line := range lines {
+		if !strings.HasPrefix(line.Text, "é") || strings.Trim(line.Text, "é") != "" {
+			t.Errorf("line %d: expected whole runes, got %q...", i, line.Text[:4])
+		}
+	}
+}
+
+func TestTextScannerTruncate(t *testing.T) {
+	lines := scanLines(t, strings.Repeat("a", 10000)+"\nnext\n", TextScannerOptions{MaxLineSize: 100, LinePolicy: LineTruncate})
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if want := strings.Repeat("a", 100-len(TruncatedMarker)) + TruncatedMarker; lines[0].Text != want || !lines[0].Truncated {
+		t.Errorf("expected truncated line %q, got %q", want, lines[0].Text)
+	}
+	if lines[1].Text != "next" || lines[1].Truncated {
+		t.Errorf("expected line %q, got %q", "next", lines[1].Text)
+	}
+}
+
+func TestTextScannerTruncateRunes(t *testing.T) {
+	lines := scanLines(t, strings.Repeat("é", 100)+"\n", TextScannerOptions{MaxLineSize: 100, LinePolicy: LineTruncate})
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(lines))
+	}
+	if want := strings.Repeat("é", 44) + TruncatedMarker; lines[0].Text != want {
+		t.Errorf("expected truncated line %q, got %q", want, lines[0].Text)
+	}
+}
+
+func TestTextScannerGrow(t *testing.T) {
+	long := strings.Repeat("a", 1000)
+	lines := scanLines(t, long+"\n", TextScannerOptions{MaxLineSize: 100, LinePolicy: LineGrow})
+	if len(lines) != 1 || lines[0].Text != long {
+		t.Fatalf("expected one line of %d bytes, got %d lines", len(long), len(lines))
+	}
+}
+
+func TestTextScannerGrowLimit(t *testing.T) {
+	long := strings.Repeat("a", 4000)
+	lines := scanLines(t, long+"\n", TextScannerOptions{MaxLineSize: 100, LinePolicy: LineGrow})
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+	want := []string{long[:1600], long[1600:3200], long[3200:]}
+	if strings.Join(texts, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %d lines, got %d lines", len(want), len(texts))
+	}
+	for i, continued := range []bool{true, true, false} {
+		if lines[i].Continued != continued {
+			t.Errorf("line %d: expected continued %v, got %v", i, continued, lines[i].Continued)
+		}
+	}
+}
+
+func TestTextScannerBinary(t *testing.T) {
+	lines := scanLines(t, "text\n\xff\xfe\x00\n", TextScannerOptions{Binary: true})
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if lines[0].Text != "text" || lines[0].Encoding != "" {
+		t.Errorf("expected plain line %q, got %q (%v)", "text", lines[0].Text, lines[0].Encoding)
+	}
+	if lines[1].Text != "//4A" || lines[1].Encoding != EncodingBase64 {
+		t.Errorf("expected base64 line %q, got %q (%v)", "//4A", lines[1].Text, lines[1].Encoding)
+	}
+}